@@ -0,0 +1,77 @@
+package envh
+
+import (
+	"reflect"
+)
+
+// Get returns variable value converted to type T, it supports
+// every type a struct field can have : int, float32, string, bool, Secret
+// and types having a decoder registered with RegisterDecoder.
+// An error is returned if variable doesn't exist or can't be converted
+func Get[T any](env Env, key string) (T, error) {
+	return decode[T](env.lookup(key), varRef{name: key})
+}
+
+// GetOr returns variable value converted to type T like Get does,
+// or defaultValue if variable doesn't exist or can't be converted
+func GetOr[T any](env Env, key string, defaultValue T) T {
+	if value, err := Get[T](env, key); err == nil {
+		return value
+	}
+
+	return defaultValue
+}
+
+// MustGet returns variable value converted to type T like Get does,
+// but it panics if an error occurred
+func MustGet[T any](env Env, key string) T {
+	value, err := Get[T](env, key)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return value
+}
+
+// Find returns value of key chain converted to type T, it supports
+// every type a struct field can have : int, float32, string, bool, Secret
+// and types having a decoder registered with RegisterDecoder.
+// An error is returned if key chain doesn't exist or can't be converted
+func Find[T any](tree EnvTree, keyChain ...string) (T, error) {
+	return decode[T](tree.lookup(keyChain), tree.ref(keyChain))
+}
+
+// FindOr returns value of key chain converted to type T like Find does,
+// or defaultValue if key chain doesn't exist or can't be converted
+func FindOr[T any](tree EnvTree, defaultValue T, keyChain ...string) T {
+	if value, err := Find[T](tree, keyChain...); err == nil {
+		return value
+	}
+
+	return defaultValue
+}
+
+// MustFind returns value of key chain converted to type T like Find does,
+// but it panics if an error occurred
+func MustFind[T any](tree EnvTree, keyChain ...string) T {
+	value, err := Find[T](tree, keyChain...)
+
+	if err != nil {
+		panic(err)
+	}
+
+	return value
+}
+
+func decode[T any](lookup func() (string, bool), ref varRef) (T, error) {
+	var value T
+
+	if err := decodeValue(true, reflect.ValueOf(&value).Elem(), lookup, ref, newPopulateOptions()); err != nil {
+		var zero T
+
+		return zero, err
+	}
+
+	return value, nil
+}
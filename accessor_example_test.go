@@ -0,0 +1,48 @@
+package envh
+
+import (
+	"fmt"
+	"os"
+)
+
+func ExampleGet() {
+	os.Clearenv()
+	setEnv("PORT", "8080")
+
+	env := NewEnv()
+
+	port, err := Get[int](env, "PORT")
+
+	if err != nil {
+		return
+	}
+
+	fmt.Println(port)
+	fmt.Println(GetOr(env, "DEBUG", false))
+	// Output:
+	// 8080
+	// false
+}
+
+func ExampleFind() {
+	os.Clearenv()
+	setEnv("APP_DB_PORT", "3306")
+
+	tree, err := NewEnvTree("^APP", "_")
+
+	if err != nil {
+		return
+	}
+
+	port, err := Find[int](tree, "APP", "DB", "PORT")
+
+	if err != nil {
+		return
+	}
+
+	fmt.Println(port)
+	fmt.Println(FindOr(tree, "localhost", "APP", "DB", "HOST"))
+	// Output:
+	// 3306
+	// localhost
+}
@@ -0,0 +1,234 @@
+package envh
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGet(t *testing.T) {
+	setEnv("TEST99_STRING", "test")
+	setEnv("TEST99_INT", "1")
+	setEnv("TEST99_FLOAT", "0.5")
+	setEnv("TEST99_BOOL", "true")
+	setEnv("TEST99_TOKEN", "hunter2")
+
+	env := NewEnv()
+
+	restoreEnvs()
+
+	s, err := Get[string](env, "TEST99_STRING")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "test", s)
+
+	i, err := Get[int](env, "TEST99_INT")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, i)
+
+	f, err := Get[float32](env, "TEST99_FLOAT")
+
+	assert.NoError(t, err)
+	assert.Equal(t, float32(0.5), f)
+
+	b, err := Get[bool](env, "TEST99_BOOL")
+
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	secret, err := Get[Secret[string]](env, "TEST99_TOKEN")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2", secret.Reveal())
+
+	_, err = Get[int](env, "TEST99_STRING")
+
+	assert.EqualError(t, err, `Value "test" of variable "TEST99_STRING" can't be converted to type "int"`)
+	assert.True(t, errors.Is(err, ErrWrongType))
+
+	_, err = Get[int](env, "TEST99_UNKNOWN")
+
+	assert.EqualError(t, err, `Variable "TEST99_UNKNOWN" not found`)
+	assert.True(t, errors.Is(err, ErrVariableNotFound))
+
+	_, err = Get[[]string](env, "TEST99_STRING")
+
+	assert.EqualError(t, err, `Type "slice" of variable "TEST99_STRING" is not supported : you must provide "int32, float32, string, boolean or struct"`)
+}
+
+func resetDecoders() {
+	decoders.Lock()
+	defer decoders.Unlock()
+
+	decoders.funcs = map[reflect.Type]func(value string) (interface{}, error){}
+}
+
+func TestGetWithRegisteredDecoder(t *testing.T) {
+	setEnv("TEST99_TIMEOUT", "1m")
+	setEnv("TEST99_URL", "http://localhost")
+
+	RegisterDecoder(time.ParseDuration)
+	RegisterDecoder(url.Parse)
+
+	defer resetDecoders()
+
+	env := NewEnv()
+
+	restoreEnvs()
+
+	d, err := Get[time.Duration](env, "TEST99_TIMEOUT")
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, d)
+
+	u, err := Get[*url.URL](env, "TEST99_URL")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", u.Host)
+
+	_, err = Get[*url.URL](env, "TEST99_TIMEOUT")
+
+	assert.NoError(t, err)
+
+	_, err = Get[time.Duration](env, "TEST99_URL")
+
+	assert.EqualError(t, err, `Value "http://localhost" of variable "TEST99_URL" can't be converted to type "time.Duration"`)
+}
+
+func TestGetWithRegisteredDecoderReturningNil(t *testing.T) {
+	setEnv("TEST99_NAME", "whatever")
+
+	RegisterDecoder(func(value string) (fmt.Stringer, error) {
+		return nil, nil
+	})
+
+	defer resetDecoders()
+
+	env := NewEnv()
+
+	restoreEnvs()
+
+	s, err := Get[fmt.Stringer](env, "TEST99_NAME")
+
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+}
+
+func TestGetOr(t *testing.T) {
+	setEnv("TEST99_INT", "1")
+	setEnv("TEST99_STRING", "test")
+
+	env := NewEnv()
+
+	restoreEnvs()
+
+	assert.Equal(t, 1, GetOr(env, "TEST99_INT", 2))
+	assert.Equal(t, 2, GetOr(env, "TEST99_UNKNOWN", 2))
+	assert.Equal(t, 2, GetOr(env, "TEST99_STRING", 2))
+}
+
+func TestMustGet(t *testing.T) {
+	setEnv("TEST99_INT", "1")
+
+	env := NewEnv()
+
+	restoreEnvs()
+
+	assert.Equal(t, 1, MustGet[int](env, "TEST99_INT"))
+	assert.PanicsWithError(t, `Variable "TEST99_UNKNOWN" not found`, func() {
+		MustGet[int](env, "TEST99_UNKNOWN")
+	})
+}
+
+func TestFind(t *testing.T) {
+	setEnv("ACCESSOR_DB_PORT", "3306")
+	setEnv("ACCESSOR_DB_HOST", "localhost")
+
+	tree, err := NewEnvTree("^ACCESSOR", "_")
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+
+	port, err := Find[int](tree, "ACCESSOR", "DB", "PORT")
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3306, port)
+
+	_, err = Find[int](tree, "ACCESSOR", "DB", "HOST")
+
+	assert.EqualError(t, err, `Value "localhost" of variable "ACCESSOR_DB_HOST" can't be converted to type "int"`)
+
+	wrongTypeErr := WrongTypeError{}
+
+	assert.True(t, errors.As(err, &wrongTypeErr))
+	assert.Equal(t, []string{"ACCESSOR", "DB", "HOST"}, wrongTypeErr.KeyChain)
+
+	subTree, err := tree.FindSubTree("ACCESSOR", "DB")
+
+	assert.NoError(t, err)
+
+	_, err = Find[int](subTree, "USER")
+
+	assert.EqualError(t, err, `Variable "ACCESSOR_DB_USER" not found`)
+}
+
+func TestFindOr(t *testing.T) {
+	setEnv("ACCESSOR_DB_PORT", "3306")
+
+	tree, err := NewEnvTree("^ACCESSOR", "_")
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3306, FindOr(tree, 5432, "ACCESSOR", "DB", "PORT"))
+	assert.Equal(t, 5432, FindOr(tree, 5432, "ACCESSOR", "DB", "NUMBER"))
+}
+
+func TestMustFind(t *testing.T) {
+	setEnv("ACCESSOR_DB_PORT", "3306")
+
+	tree, err := NewEnvTree("^ACCESSOR", "_")
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "3306", MustFind[string](tree, "ACCESSOR", "DB", "PORT"))
+	assert.PanicsWithError(t, `Value "3306" of variable "ACCESSOR_DB_PORT" can't be converted to type "bool"`, func() {
+		MustFind[bool](tree, "ACCESSOR", "DB", "PORT")
+	})
+}
+
+func TestPopulateWithRegisteredDecoder(t *testing.T) {
+	setEnv("OPTIONSDECODER_Timeout", "1m")
+
+	RegisterDecoder(time.ParseDuration)
+
+	defer resetDecoders()
+
+	actual := OPTIONSDECODER{}
+
+	tree, err := NewEnvTree("^OPTIONSDECODER", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual)
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Minute, actual.Timeout)
+
+	err = tree.Populate(&actual, WithDecoder(func(value string) (time.Duration, error) {
+		return time.Hour, nil
+	}))
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, actual.Timeout, "Decoder given as option must take precedence")
+}
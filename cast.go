@@ -4,57 +4,64 @@ import (
 	"strconv"
 )
 
-func getString(fun func() (string, bool)) (string, error) {
+// varRef describes the variable a value is looked up from,
+// it gives context to errors
+type varRef struct {
+	keyChain []string
+	name     string
+}
+
+func getString(fun func() (string, bool), ref varRef) (string, error) {
 	if v, ok := fun(); ok {
 		return v, nil
 	}
 
-	return "", VariableNotFoundError{}
+	return "", VariableNotFoundError{ref.keyChain, ref.name}
 }
 
-func getInt(fun func() (string, bool)) (int, error) {
+func getInt(fun func() (string, bool), ref varRef) (int, error) {
 	v, ok := fun()
 
 	if !ok {
-		return 0, VariableNotFoundError{}
+		return 0, VariableNotFoundError{ref.keyChain, ref.name}
 	}
 
 	i, err := strconv.Atoi(v)
 
 	if err != nil {
-		return 0, WrongTypeError{v, "int"}
+		return 0, WrongTypeError{v, "int", ref.keyChain, ref.name, err, false}
 	}
 
 	return i, nil
 }
 
-func getFloat(fun func() (string, bool)) (float32, error) {
+func getFloat(fun func() (string, bool), ref varRef) (float32, error) {
 	v, ok := fun()
 
 	if !ok {
-		return 0, VariableNotFoundError{}
+		return 0, VariableNotFoundError{ref.keyChain, ref.name}
 	}
 
 	f, err := strconv.ParseFloat(v, 32)
 
 	if err != nil {
-		return 0, WrongTypeError{v, "float"}
+		return 0, WrongTypeError{v, "float", ref.keyChain, ref.name, err, false}
 	}
 
 	return float32(f), nil
 }
 
-func getBool(fun func() (string, bool)) (bool, error) {
+func getBool(fun func() (string, bool), ref varRef) (bool, error) {
 	v, ok := fun()
 
 	if !ok {
-		return false, VariableNotFoundError{}
+		return false, VariableNotFoundError{ref.keyChain, ref.name}
 	}
 
 	b, err := strconv.ParseBool(v)
 
 	if err != nil {
-		return false, WrongTypeError{v, "bool"}
+		return false, WrongTypeError{v, "bool", ref.keyChain, ref.name, err, false}
 	}
 
 	return b, nil
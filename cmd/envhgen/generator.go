@@ -0,0 +1,404 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/antham/envh/internal/tagspec"
+)
+
+const envhImportPath = "github.com/antham/envh"
+
+var basicGetters = map[string]string{
+	"int":     "Int",
+	"float32": "Float",
+	"string":  "String",
+	"bool":    "Bool",
+}
+
+// getterKinds gives kind of values returned by Loader getters
+var getterKinds = map[string]reflect.Kind{
+	"Int":    reflect.Int,
+	"Float":  reflect.Float32,
+	"String": reflect.String,
+	"Bool":   reflect.Bool,
+}
+
+// fieldType describes how a field is filled, either with a Loader
+// getter, or field by field when it's a nested struct
+type fieldType struct {
+	getter     string
+	conversion string
+	secret     bool
+	structType *ast.StructType
+	name       string
+}
+
+// structNode is a struct filled by generated code,
+// children are nested structs
+type structNode struct {
+	name     string
+	expr     string
+	chain    string
+	path     string
+	children []*structNode
+}
+
+type generator struct {
+	pkg        string
+	typeName   string
+	types      map[string]ast.Expr
+	methods    map[string]map[string]bool
+	envhNames  map[string]bool
+	buf        bytes.Buffer
+	chainCount int
+}
+
+func newGenerator(files []*ast.File) *generator {
+	g := &generator{types: map[string]ast.Expr{}, methods: map[string]map[string]bool{}, envhNames: map[string]bool{}}
+
+	for _, f := range files {
+		g.pkg = f.Name.Name
+
+		for _, imp := range f.Imports {
+			if path, _ := strconv.Unquote(imp.Path.Value); path == envhImportPath {
+				name := "envh"
+
+				if imp.Name != nil {
+					name = imp.Name.Name
+				}
+
+				g.envhNames[name] = true
+			}
+		}
+
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if s, ok := spec.(*ast.TypeSpec); ok {
+						g.types[s.Name.Name] = s.Type
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil || len(d.Recv.List) == 0 {
+					continue
+				}
+
+				recv := d.Recv.List[0].Type
+
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+
+				if ident, ok := recv.(*ast.Ident); ok {
+					if g.methods[ident.Name] == nil {
+						g.methods[ident.Name] = map[string]bool{}
+					}
+
+					g.methods[ident.Name][d.Name.Name] = true
+				}
+			}
+		}
+	}
+
+	return g
+}
+
+// generate returns source of a function filling struct typeName
+func generate(files []*ast.File, typeName string, strict bool) ([]byte, error) {
+	g := newGenerator(files)
+	g.typeName = typeName
+	st, ok := g.types[typeName].(*ast.StructType)
+
+	if !ok {
+		return nil, fmt.Errorf(`struct type "%s" not found`, typeName)
+	}
+
+	if g.methods[typeName]["Walk"] {
+		return nil, fmt.Errorf(`type "%s" implements StructWalker which isn't supported by generated loaders`, typeName)
+	}
+
+	root := &structNode{typeName, "c", g.newChain(), "", nil}
+	behaviour := "PopulateStruct"
+
+	if strict {
+		behaviour = "PopulateStructWithStrictMode"
+	}
+
+	g.printf("// Code generated by envhgen; DO NOT EDIT.\n\n")
+	g.printf("package %s\n\n", g.pkg)
+	g.printf("import \"%s\"\n\n", envhImportPath)
+	g.printf("// Load%s fills c with datas extracted from tree like %s does\n", typeName, behaviour)
+	g.printf("func Load%s(tree envh.EnvTree, c *%s) error {\n", typeName, typeName)
+	g.printf("l := envh.NewLoader(tree, %t)\n", strict)
+	g.printf("%s := []string{%q}\n", root.chain, typeName)
+
+	queue := []struct {
+		node *structNode
+		st   *ast.StructType
+	}{{root, st}}
+
+	for len(queue) > 0 {
+		node, st := queue[0].node, queue[0].st
+		queue = queue[1:]
+
+		for _, field := range st.Fields.List {
+			for _, name := range fieldNames(field) {
+				if !ast.IsExported(name) {
+					continue
+				}
+
+				child, childType, err := g.field(node, field, name)
+
+				if err != nil {
+					return nil, err
+				}
+
+				if child != nil {
+					node.children = append(node.children, child)
+					queue = append(queue, struct {
+						node *structNode
+						st   *ast.StructType
+					}{child, childType})
+				}
+			}
+		}
+	}
+
+	g.hooks(root)
+	g.printf("return l.Err()\n}\n")
+
+	return format.Source(g.buf.Bytes())
+}
+
+// field writes code filling a field, it returns node
+// and type of the field if it's a nested struct
+func (g *generator) field(parent *structNode, field *ast.Field, name string) (*structNode, *ast.StructType, error) {
+	path := strings.TrimPrefix(parent.path+"."+name, ".")
+	typ, err := g.fieldType(field.Type)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf(`field "%s.%s" : %s`, g.typeName, path, err)
+	}
+
+	desc, err := g.fieldDescription(field, name, path, typ)
+
+	if err != nil {
+		return nil, nil, fmt.Errorf(`field "%s.%s" : %s`, g.typeName, path, err)
+	}
+
+	expr := parent.expr + "." + name
+
+	if typ.structType != nil {
+		child := &structNode{typ.name, expr, g.newChain(), path, nil}
+		g.printf("%s := l.Struct(%s, %s)\n", child.chain, parent.chain, desc)
+
+		return child, typ.structType, nil
+	}
+
+	value := fmt.Sprintf("l.%s(%s, %s)", typ.getter, parent.chain, desc)
+
+	if typ.conversion != "" {
+		value = fmt.Sprintf("%s(%s)", typ.conversion, value)
+	}
+
+	if typ.secret {
+		value = fmt.Sprintf("envh.NewSecret(%s)", value)
+	}
+
+	g.printf("%s = %s\n", expr, value)
+
+	return nil, nil, nil
+}
+
+func (g *generator) fieldType(expr ast.Expr) (fieldType, error) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if getter, ok := basicGetters[e.Name]; ok {
+			return fieldType{getter: getter}, nil
+		}
+
+		switch underlying := g.types[e.Name].(type) {
+		case *ast.StructType:
+			return fieldType{structType: underlying, name: e.Name}, nil
+		case *ast.Ident:
+			if getter, ok := basicGetters[underlying.Name]; ok {
+				return fieldType{getter: getter, conversion: e.Name}, nil
+			}
+		}
+	case *ast.StructType:
+		return fieldType{structType: e}, nil
+	case *ast.IndexExpr:
+		if sel, ok := e.X.(*ast.SelectorExpr); ok && sel.Sel.Name == "Secret" && g.isEnvh(sel.X) {
+			inner, err := g.fieldType(e.Index)
+
+			if err == nil && inner.getter != "" {
+				inner.secret = true
+
+				return inner, nil
+			}
+		}
+	}
+
+	return fieldType{}, fmt.Errorf(`type "%s" is not supported : you must provide "int, float32, string, boolean, a type based on them, a Secret of them or struct"`, types.ExprString(expr))
+}
+
+// fieldDescription returns an envh.Field literal built from field tags,
+// a Secret field is always considered as secret. Tags are checked
+// so a malformed one fails generation rather than loading
+func (g *generator) fieldDescription(field *ast.Field, name string, path string, typ fieldType) (string, error) {
+	secret := typ.secret
+	tag := reflect.StructTag("")
+
+	if field.Tag != nil {
+		value, err := strconv.Unquote(field.Tag.Value)
+
+		if err != nil {
+			return "", err
+		}
+
+		tag = reflect.StructTag(value)
+	}
+
+	keys := []string{name}
+
+	if value, ok := tag.Lookup("envh"); ok {
+		options := strings.Split(value, ",")
+
+		if k := tagspec.ParseKeys(options[0]); len(k) > 0 {
+			keys = k
+		}
+
+		for _, option := range options[1:] {
+			if strings.TrimSpace(option) != tagspec.SecretOption {
+				return "", fmt.Errorf(`tag envh:"%s" is invalid : option "%s" doesn't exist`, value, option)
+			}
+
+			secret = true
+		}
+	}
+
+	rules := tag.Get("validate")
+	parsed, err := tagspec.ParseRules(rules)
+
+	if err != nil {
+		return "", fmt.Errorf(`tag validate:"%s" is invalid : %s`, rules, err)
+	}
+
+	for _, r := range parsed {
+		if tagspec.Rules[r.Name].CrossField {
+			return "", fmt.Errorf(`rule "%s" involves a sibling field and isn't supported by generated loaders`, r.Name)
+		}
+	}
+
+	if value, ok := tag.Lookup("default"); ok {
+		if t := tagspec.CheckValue(value, getterKinds[typ.getter]); t != "" {
+			return "", fmt.Errorf(`default value "%s" can't be converted to type "%s"`, value, t)
+		}
+	}
+
+	quoted := []string{}
+
+	for _, k := range keys {
+		quoted = append(quoted, strconv.Quote(k))
+	}
+
+	attributes := []string{fmt.Sprintf("Keys: []string{%s}", strings.Join(quoted, ", ")), fmt.Sprintf("Path: %q", path)}
+
+	if value, ok := tag.Lookup("default"); ok {
+		attributes = append(attributes, fmt.Sprintf("Default: %q", value), "HasDefault: true")
+	}
+
+	if secret {
+		attributes = append(attributes, "Secret: true")
+	}
+
+	if value, ok := tag.Lookup("deprecated"); ok {
+		attributes = append(attributes, fmt.Sprintf("Deprecation: %q", value), "Deprecated: true")
+	}
+
+	if rules != "" {
+		attributes = append(attributes, fmt.Sprintf("Rules: %q", rules))
+	}
+
+	return fmt.Sprintf("envh.Field{%s}", strings.Join(attributes, ", ")), nil
+}
+
+// hooks writes calls to AfterPopulate and Validate methods,
+// nested structs first
+func (g *generator) hooks(node *structNode) {
+	for _, child := range node.children {
+		g.hooks(child)
+	}
+
+	methods := g.methods[node.name]
+
+	if node.name == "" || !methods["AfterPopulate"] && !methods["Validate"] {
+		return
+	}
+
+	g.printf("l.Hook(%s, %q, func(tree *envh.EnvTree) error {\n", node.chain, node.path)
+
+	switch {
+	case methods["AfterPopulate"] && methods["Validate"]:
+		g.printf("if err := %s.AfterPopulate(tree); err != nil {\nreturn err\n}\n\nreturn %s.Validate()\n", node.expr, node.expr)
+	case methods["AfterPopulate"]:
+		g.printf("return %s.AfterPopulate(tree)\n", node.expr)
+	default:
+		g.printf("return %s.Validate()\n", node.expr)
+	}
+
+	g.printf("})\n")
+}
+
+func (g *generator) isEnvh(expr ast.Expr) bool {
+	ident, ok := expr.(*ast.Ident)
+
+	return ok && g.envhNames[ident.Name]
+}
+
+func (g *generator) newChain() string {
+	g.chainCount++
+
+	return fmt.Sprintf("chain%d", g.chainCount-1)
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// fieldNames returns names of a field, an embedded
+// field is named after its type
+func fieldNames(field *ast.Field) []string {
+	if len(field.Names) > 0 {
+		names := []string{}
+
+		for _, n := range field.Names {
+			names = append(names, n.Name)
+		}
+
+		return names
+	}
+
+	typ := field.Type
+
+	if star, ok := typ.(*ast.StarExpr); ok {
+		typ = star.X
+	}
+
+	switch t := typ.(type) {
+	case *ast.Ident:
+		return []string{t.Name}
+	case *ast.SelectorExpr:
+		return []string{t.Sel.Name}
+	}
+
+	return []string{}
+}
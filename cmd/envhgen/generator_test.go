@@ -0,0 +1,177 @@
+package main
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func parseSource(t *testing.T, src string) []*ast.File {
+	f, err := parser.ParseFile(token.NewFileSet(), "config.go", src, 0)
+
+	assert.NoError(t, err)
+
+	return []*ast.File{f}
+}
+
+func TestGenerateIsUpToDate(t *testing.T) {
+	files := []*ast.File{}
+
+	for _, filename := range []string{"internal/fixture/config.go"} {
+		f, err := parser.ParseFile(token.NewFileSet(), filename, nil, 0)
+
+		assert.NoError(t, err)
+
+		files = append(files, f)
+	}
+
+	src, err := generate(files, "CONFIG", false)
+
+	assert.NoError(t, err)
+
+	expected, err := os.ReadFile("internal/fixture/config_envh.go")
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(src), "run go generate ./... to update generated code")
+}
+
+func TestGenerateWithStrictMode(t *testing.T) {
+	src, err := generate(parseSource(t, `package app
+
+type APP struct {
+	PORT int
+}`), "APP", true)
+
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "like PopulateStructWithStrictMode does")
+	assert.Contains(t, string(src), "l := envh.NewLoader(tree, true)")
+	assert.Contains(t, string(src), `c.PORT = l.Int(chain0, envh.Field{Keys: []string{"PORT"}, Path: "PORT"})`)
+}
+
+func TestGenerateWithErrors(t *testing.T) {
+	type g struct {
+		src string
+		err string
+	}
+
+	tests := []g{
+		{
+			`package app
+
+type APP int`,
+			`struct type "APP" not found`,
+		},
+		{
+			`package app
+
+import "github.com/antham/envh"
+
+type APP struct{}
+
+func (a *APP) Walk(tree *envh.EnvTree, keyChain []string) (bool, error) {
+	return false, nil
+}`,
+			`type "APP" implements StructWalker which isn't supported by generated loaders`,
+		},
+		{
+			`package app
+
+type APP struct {
+	DB struct {
+		HOSTS map[string]string
+	}
+}`,
+			`field "APP.DB.HOSTS" : type "map[string]string" is not supported : you must provide "int, float32, string, boolean, a type based on them, a Secret of them or struct"`,
+		},
+		{
+			`package app
+
+import "time"
+
+type APP struct {
+	TIMEOUT time.Duration
+}`,
+			`field "APP.TIMEOUT" : type "time.Duration" is not supported : you must provide "int, float32, string, boolean, a type based on them, a Secret of them or struct"`,
+		},
+		{
+			`package app
+
+type APP struct {
+	PORT int ` + "`envh:\",whatever\"`" + `
+}`,
+			`field "APP.PORT" : tag envh:",whatever" is invalid : option "whatever" doesn't exist`,
+		},
+		{
+			`package app
+
+type APP struct {
+	MIN int
+	MAX int ` + "`validate:\"min=1,gtfield=MIN\"`" + `
+}`,
+			`field "APP.MAX" : rule "gtfield" involves a sibling field and isn't supported by generated loaders`,
+		},
+		{
+			`package app
+
+type APP struct {
+	PORT int ` + "`default:\"abc\"`" + `
+}`,
+			`field "APP.PORT" : default value "abc" can't be converted to type "int"`,
+		},
+		{
+			`package app
+
+import "github.com/antham/envh"
+
+type APP struct {
+	RATIO envh.Secret[float32] ` + "`default:\"half\"`" + `
+}`,
+			`field "APP.RATIO" : default value "half" can't be converted to type "float"`,
+		},
+		{
+			`package app
+
+type APP struct {
+	HOST string ` + "`validate:\"whatever\"`" + `
+}`,
+			`field "APP.HOST" : tag validate:"whatever" is invalid : rule "whatever" doesn't exist`,
+		},
+		{
+			`package app
+
+type APP struct {
+	HOST string ` + "`validate:\"nonempty,min\"`" + `
+}`,
+			`field "APP.HOST" : tag validate:"nonempty,min" is invalid : rule "min" requires a parameter`,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := generate(parseSource(t, test.src), "APP", false)
+
+		assert.EqualError(t, err, test.err)
+	}
+}
+
+func TestFieldNames(t *testing.T) {
+	files := parseSource(t, `package app
+
+type APP struct {
+	A, B string
+	DB
+	*CACHE
+	other.MAILER
+}`)
+
+	names := []string{}
+
+	for _, field := range files[0].Decls[0].(*ast.GenDecl).Specs[0].(*ast.TypeSpec).Type.(*ast.StructType).Fields.List {
+		names = append(names, fieldNames(field)...)
+	}
+
+	assert.Equal(t, []string{"A", "B", "DB", "CACHE", "MAILER"}, names)
+}
@@ -0,0 +1,53 @@
+// Package fixture holds a config struct whose loader is generated
+// with envhgen, it's used to check generated code behaves like PopulateStruct
+package fixture
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/antham/envh"
+)
+
+//go:generate go run github.com/antham/envh/cmd/envhgen -type CONFIG
+
+// Level is a log level
+type Level string
+
+// DB holds database settings
+type DB struct {
+	HOST     string `validate:"hostname"`
+	PORT     int    `envh:"PORT|DBPORT" default:"5432" validate:"port"`
+	PASSWORD envh.Secret[string]
+	URL      string
+}
+
+// AfterPopulate defines URL
+func (d *DB) AfterPopulate(tree *envh.EnvTree) error {
+	d.URL = fmt.Sprintf("postgres://%s:%d", d.HOST, d.PORT)
+
+	return nil
+}
+
+// Validate checks database isn't exposed
+func (d DB) Validate() error {
+	if d.HOST == "0.0.0.0" {
+		return errors.New("database must not listen on every interface")
+	}
+
+	return nil
+}
+
+// CONFIG is populated with a generated loader
+type CONFIG struct {
+	DB     DB      `envh:"DB|DATABASE"`
+	LEVEL  Level   `default:"info" validate:"oneof=debug info"`
+	RATIO  float32 `validate:"max=1"`
+	DEBUG  bool    `deprecated:"use LEVEL"`
+	TOKEN  string  `envh:",secret" validate:"len=8"`
+	MAILER struct {
+		HOST    string
+		ENABLED bool `default:"true"`
+	}
+	internal string
+}
@@ -0,0 +1,31 @@
+// Code generated by envhgen; DO NOT EDIT.
+
+package fixture
+
+import "github.com/antham/envh"
+
+// LoadCONFIG fills c with datas extracted from tree like PopulateStruct does
+func LoadCONFIG(tree envh.EnvTree, c *CONFIG) error {
+	l := envh.NewLoader(tree, false)
+	chain0 := []string{"CONFIG"}
+	chain1 := l.Struct(chain0, envh.Field{Keys: []string{"DB", "DATABASE"}, Path: "DB"})
+	c.LEVEL = Level(l.String(chain0, envh.Field{Keys: []string{"LEVEL"}, Path: "LEVEL", Default: "info", HasDefault: true, Rules: "oneof=debug info"}))
+	c.RATIO = l.Float(chain0, envh.Field{Keys: []string{"RATIO"}, Path: "RATIO", Rules: "max=1"})
+	c.DEBUG = l.Bool(chain0, envh.Field{Keys: []string{"DEBUG"}, Path: "DEBUG", Deprecation: "use LEVEL", Deprecated: true})
+	c.TOKEN = l.String(chain0, envh.Field{Keys: []string{"TOKEN"}, Path: "TOKEN", Secret: true, Rules: "len=8"})
+	chain2 := l.Struct(chain0, envh.Field{Keys: []string{"MAILER"}, Path: "MAILER"})
+	c.DB.HOST = l.String(chain1, envh.Field{Keys: []string{"HOST"}, Path: "DB.HOST", Rules: "hostname"})
+	c.DB.PORT = l.Int(chain1, envh.Field{Keys: []string{"PORT", "DBPORT"}, Path: "DB.PORT", Default: "5432", HasDefault: true, Rules: "port"})
+	c.DB.PASSWORD = envh.NewSecret(l.String(chain1, envh.Field{Keys: []string{"PASSWORD"}, Path: "DB.PASSWORD", Secret: true}))
+	c.DB.URL = l.String(chain1, envh.Field{Keys: []string{"URL"}, Path: "DB.URL"})
+	c.MAILER.HOST = l.String(chain2, envh.Field{Keys: []string{"HOST"}, Path: "MAILER.HOST"})
+	c.MAILER.ENABLED = l.Bool(chain2, envh.Field{Keys: []string{"ENABLED"}, Path: "MAILER.ENABLED", Default: "true", HasDefault: true})
+	l.Hook(chain1, "DB", func(tree *envh.EnvTree) error {
+		if err := c.DB.AfterPopulate(tree); err != nil {
+			return err
+		}
+
+		return c.DB.Validate()
+	})
+	return l.Err()
+}
@@ -0,0 +1,82 @@
+package fixture
+
+import (
+	"os"
+	"testing"
+
+	"github.com/antham/envh"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadCONFIG(t *testing.T) {
+	type g struct {
+		envs map[string]string
+		err  string
+	}
+
+	tests := []g{
+		{
+			map[string]string{
+				"CONFIG_DATABASE_HOST":     "localhost",
+				"CONFIG_DATABASE_DBPORT":   "3306",
+				"CONFIG_DATABASE_PASSWORD": "hunter2",
+				"CONFIG_RATIO":             "0.5",
+				"CONFIG_DEBUG":             "true",
+				"CONFIG_TOKEN":             "12345678",
+				"CONFIG_MAILER_HOST":       "127.0.0.1",
+			},
+			"",
+		},
+		{
+			map[string]string{
+				"CONFIG_DB_HOST":     "local_host",
+				"CONFIG_DB_PORT":     "http",
+				"CONFIG_LEVEL":       "trace",
+				"CONFIG_RATIO":       "2",
+				"CONFIG_TOKEN":       "123",
+				"CONFIG_MAILER_HOST": "127.0.0.1",
+			},
+			`5 error(s) occurred while populating struct :
+  - Field "LEVEL" : Value "trace" of variable "CONFIG_LEVEL" is invalid : must be one of "debug", "info"
+  - Field "RATIO" : Value "2" of variable "CONFIG_RATIO" is invalid : must be lower than or equal to 1
+  - Field "TOKEN" : Value "******" of variable "CONFIG_TOKEN" is invalid : length must be equal to 8
+  - Field "DB.HOST" : Value "local_host" of variable "CONFIG_DB_HOST" is invalid : must be a valid hostname
+  - Field "DB.PORT" : Value "http" of variable "CONFIG_DB_PORT" can't be converted to type "int"`,
+		},
+		{
+			map[string]string{
+				"CONFIG_DB_HOST": "0.0.0.0",
+			},
+			`1 error(s) occurred while populating struct :
+  - Field "DB" : database must not listen on every interface`,
+		},
+	}
+
+	for _, test := range tests {
+		os.Clearenv()
+
+		for key, value := range test.envs {
+			assert.NoError(t, os.Setenv(key, value))
+		}
+
+		tree, err := envh.NewEnvTree("^CONFIG", "_")
+
+		assert.NoError(t, err)
+
+		generated := CONFIG{}
+		populated := CONFIG{}
+
+		generatedErr := LoadCONFIG(tree, &generated)
+		populatedErr := tree.PopulateStruct(&populated)
+
+		if test.err == "" {
+			assert.NoError(t, generatedErr)
+			assert.NoError(t, populatedErr)
+		} else {
+			assert.EqualError(t, generatedErr, test.err)
+			assert.EqualError(t, populatedErr, test.err)
+		}
+
+		assert.Equal(t, populated, generated)
+	}
+}
@@ -0,0 +1,106 @@
+// Command envhgen generates a function filling a config struct from an envh.EnvTree
+// without reflection. Generated code calls typed getters of an envh.Loader for every field,
+// so it behaves like PopulateStruct (envh, default, deprecated and validate tags, hooks,
+// errors gathered in a PopulateError) while a field of an unsupported type is
+// reported when code is generated instead of when config is loaded.
+//
+// It's meant to be used with go generate, for instance :
+//
+//	//go:generate go run github.com/antham/envh/cmd/envhgen -type CONFIG
+//
+// generates a LoadCONFIG(tree envh.EnvTree, c *CONFIG) error function in config_envh.go.
+// Supported fields are int, float32, string, bool, types based on them,
+// envh.Secret of those types and nested structs. Rules involving
+// a sibling field and StructWalker aren't supported.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	typeName := flag.String("type", "", "name of the config struct type, mandatory")
+	output := flag.String("output", "", "output file, default is <type>_envh.go in lower case")
+	strict := flag.Bool("strict", false, "report missing variables like PopulateStructWithStrictMode")
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage : envhgen -type CONFIG [-output file] [-strict] [files]\n")
+		flag.PrintDefaults()
+	}
+
+	flag.Parse()
+
+	if *typeName == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *output == "" {
+		*output = strings.ToLower(*typeName) + "_envh.go"
+	}
+
+	if err := run(*typeName, *output, *strict, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "envhgen : %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(typeName string, output string, strict bool, filenames []string) error {
+	if len(filenames) == 0 {
+		var err error
+
+		if filenames, err = packageFiles(".", output); err != nil {
+			return err
+		}
+	}
+
+	files := []*ast.File{}
+	fset := token.NewFileSet()
+
+	for _, filename := range filenames {
+		f, err := parser.ParseFile(fset, filename, nil, 0)
+
+		if err != nil {
+			return err
+		}
+
+		files = append(files, f)
+	}
+
+	src, err := generate(files, typeName, strict)
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, src, 0o644)
+}
+
+// packageFiles returns go files of a directory, test
+// files and previously generated file are excluded
+func packageFiles(dir string, output string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+
+	if err != nil {
+		return nil, err
+	}
+
+	filenames := []string{}
+
+	for _, m := range matches {
+		if strings.HasSuffix(m, "_test.go") || filepath.Base(m) == filepath.Base(output) {
+			continue
+		}
+
+		filenames = append(filenames, m)
+	}
+
+	return filenames, nil
+}
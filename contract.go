@@ -0,0 +1,107 @@
+package envh
+
+import (
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Declaration describes a variable a library or an application needs
+type Declaration struct {
+	// Owner identifies who declared the variable, a package path for instance
+	Owner string
+	// Name is the full variable name
+	Name string
+	// Type is filled when the variable is declared, from the type it's converted to
+	Type string
+	// Required is true if the variable must be defined
+	Required bool
+	// Default is used if the variable is not defined, it must be convertible to declared type
+	Default string
+	// Description explains what the variable is used for
+	Description string
+}
+
+type declaration struct {
+	Declaration
+	typ reflect.Type
+}
+
+var contract = struct {
+	sync.RWMutex
+	declarations []declaration
+}{}
+
+// Declare adds a variable converted to type T to the global contract, it's meant
+// to be called from init functions of libraries, so an application can check
+// with ValidateContract every variable they need before anything starts.
+// Every type a struct field can have is supported
+func Declare[T any](d Declaration) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	d.Type = typ.String()
+
+	contract.Lock()
+	defer contract.Unlock()
+
+	contract.declarations = append(contract.declarations, declaration{d, typ})
+}
+
+// Declarations returns every declared variable sorted by name then owner
+func Declarations() []Declaration {
+	declarations := []Declaration{}
+
+	for _, d := range sortedDeclarations() {
+		declarations = append(declarations, d.Declaration)
+	}
+
+	return declarations
+}
+
+// ValidateContract checks every declared variable against env : a required variable
+// must be defined and every defined value, or default one, must be convertible
+// to the declared type. Every violation is gathered in a ContractError
+func ValidateContract(env Env) error {
+	violations := []ContractViolation{}
+	opts := newPopulateOptions()
+
+	for _, d := range sortedDeclarations() {
+		tag := fieldTag{name: d.Name, keys: []string{d.Name}, secret: isSecretType(d.typ), defaultValue: d.Default, hasDefault: d.Default != ""}
+		lookup := func() (string, bool) {
+			if value, ok := env.lookup(d.Name)(); ok {
+				return value, true
+			}
+
+			return tag.defaultValue, tag.hasDefault
+		}
+
+		if err := decodeValue(d.Required, reflect.New(d.typ).Elem(), lookup, varRef{name: d.Name}, opts); err != nil {
+			if tag.secret {
+				err = markSecret(err)
+			}
+
+			violations = append(violations, ContractViolation{d.Owner, d.Name, err})
+		}
+	}
+
+	if len(violations) > 0 {
+		return ContractError{violations}
+	}
+
+	return nil
+}
+
+func sortedDeclarations() []declaration {
+	contract.RLock()
+	declarations := append([]declaration{}, contract.declarations...)
+	contract.RUnlock()
+
+	sort.SliceStable(declarations, func(i, j int) bool {
+		if declarations[i].Name != declarations[j].Name {
+			return declarations[i].Name < declarations[j].Name
+		}
+
+		return declarations[i].Owner < declarations[j].Owner
+	})
+
+	return declarations
+}
@@ -0,0 +1,23 @@
+package envh
+
+import (
+	"fmt"
+	"os"
+)
+
+func ExampleValidateContract() {
+	os.Clearenv()
+	setEnv("MAILER_PORT", "whatever")
+
+	// declarations are usually made in init functions of libraries
+	Declare[string](Declaration{Owner: "mailer", Name: "MAILER_HOST", Required: true, Description: "SMTP host"})
+	Declare[int](Declaration{Owner: "mailer", Name: "MAILER_PORT", Default: "25", Description: "SMTP port"})
+
+	fmt.Println(ValidateContract(NewEnv()))
+
+	resetContract()
+	// Output:
+	// 2 error(s) occurred while validating contract :
+	//   - Variable "MAILER_HOST" not found (declared by "mailer")
+	//   - Value "whatever" of variable "MAILER_PORT" can't be converted to type "int" (declared by "mailer")
+}
@@ -0,0 +1,86 @@
+package envh
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resetContract() {
+	contract.Lock()
+	defer contract.Unlock()
+
+	contract.declarations = []declaration{}
+}
+
+func TestDeclarations(t *testing.T) {
+	Declare[int](Declaration{Owner: "db", Name: "TEST99_DB_PORT", Required: true, Description: "database port"})
+	Declare[string](Declaration{Owner: "db", Name: "TEST99_DB_HOST", Default: "localhost"})
+	Declare[string](Declaration{Owner: "cache", Name: "TEST99_DB_HOST"})
+
+	defer resetContract()
+
+	assert.Equal(t, []Declaration{
+		{"cache", "TEST99_DB_HOST", "string", false, "", ""},
+		{"db", "TEST99_DB_HOST", "string", false, "localhost", ""},
+		{"db", "TEST99_DB_PORT", "int", true, "", "database port"},
+	}, Declarations())
+}
+
+func TestValidateContract(t *testing.T) {
+	setEnv("TEST99_DB_PORT", "3306")
+	setEnv("TEST99_DEBUG", "true")
+	setEnv("TEST99_TIMEOUT", "1m")
+
+	Declare[int](Declaration{Owner: "db", Name: "TEST99_DB_PORT", Required: true})
+	Declare[string](Declaration{Owner: "db", Name: "TEST99_DB_HOST", Required: true, Default: "localhost"})
+	Declare[bool](Declaration{Owner: "app", Name: "TEST99_DEBUG"})
+	Declare[float32](Declaration{Owner: "app", Name: "TEST99_RATIO"})
+	Declare[time.Duration](Declaration{Owner: "http", Name: "TEST99_TIMEOUT"})
+
+	RegisterDecoder(time.ParseDuration)
+
+	defer resetContract()
+	defer resetDecoders()
+
+	err := ValidateContract(NewEnv())
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+}
+
+func TestValidateContractWithViolations(t *testing.T) {
+	setEnv("TEST99_DB_PORT", "whatever")
+	setEnv("TEST99_DB_PASSWORD", "hunter2")
+	setEnv("TEST99_SESSION", "hunter2")
+
+	Declare[int](Declaration{Owner: "db", Name: "TEST99_DB_PORT", Required: true})
+	Declare[int](Declaration{Owner: "db", Name: "TEST99_DB_PASSWORD"})
+	Declare[string](Declaration{Owner: "db", Name: "TEST99_DB_HOST", Required: true})
+	Declare[Secret[int]](Declaration{Name: "TEST99_SESSION"})
+	Declare[int](Declaration{Owner: "app", Name: "TEST99_WORKERS", Default: "many"})
+
+	defer resetContract()
+
+	err := ValidateContract(NewEnv())
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `5 error(s) occurred while validating contract :
+  - Variable "TEST99_DB_HOST" not found (declared by "db")
+  - Value "******" of variable "TEST99_DB_PASSWORD" can't be converted to type "int" (declared by "db")
+  - Value "whatever" of variable "TEST99_DB_PORT" can't be converted to type "int" (declared by "db")
+  - Value "******" of variable "TEST99_SESSION" can't be converted to type "int"
+  - Value "many" of variable "TEST99_WORKERS" can't be converted to type "int" (declared by "app")`)
+	assert.True(t, errors.Is(err, ErrVariableNotFound))
+	assert.True(t, errors.Is(err, ErrWrongType))
+
+	violation := ContractViolation{}
+
+	assert.True(t, errors.As(err, &violation))
+	assert.Equal(t, "db", violation.Owner)
+	assert.Equal(t, "TEST99_DB_HOST", violation.Variable)
+}
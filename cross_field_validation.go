@@ -0,0 +1,210 @@
+package envh
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/antham/envh/internal/tagspec"
+)
+
+// fieldState describes a populated field when checking
+// rules involving several fields
+type fieldState struct {
+	value   reflect.Value
+	defined bool
+	ref     varRef
+	secret  bool
+}
+
+type crossFieldRuleChecker func(field fieldState, sibling fieldState, param string) (message string, err error)
+
+var crossFieldRuleCheckers = map[string]crossFieldRuleChecker{
+	"required_if":      checkRequiredIf,
+	"required_with":    checkRequiredWith,
+	"required_without": checkRequiredWithout,
+	"excluded_with":    checkExcludedWith,
+	"gtfield":          checkGtField,
+	"gtefield":         checkGteField,
+	"ltfield":          checkLtField,
+	"ltefield":         checkLteField,
+}
+
+func isCrossFieldRule(name string) bool {
+	return tagspec.Rules[name].CrossField
+}
+
+// validateCrossFields walks a populated struct and checks every rule
+// involving a sibling field, it must be called once whole struct is populated
+// to not depend on the order fields are defined. Fields which failed to be populated
+// or which were handled by a StructWalker are skipped, they would give misleading errors
+func validateCrossFields(tree *EnvTree, value reflect.Value, chain []string, path []string, opts *populateOptions, errs *[]FieldError, bypassed [][]string) {
+	for _, f := range opts.plan(value.Type()).fields {
+		if f.crossField {
+			keyChain := appendKey(chain, f.tag.resolveKey(tree, chain))
+
+			if isSkipped(*errs, bypassed, keyChain) {
+				continue
+			}
+
+			if err := validateCrossField(tree, value, f, chain, keyChain, opts, *errs, bypassed); err != nil {
+				*errs = append(*errs, FieldError{keyChain, strings.Join(appendKey(path, f.field.Name), "."), err})
+			}
+		}
+
+		if isNestedStruct(f.field.Type, opts) {
+			validateCrossFields(tree, value.Field(f.index), opts.structKeyChain(appendKey(chain, f.tag.resolveKey(tree, chain))), appendKey(path, f.field.Name), opts, errs, bypassed)
+		}
+	}
+}
+
+// isSkipped returns true if a field living at key chain failed
+// to be populated or if it was handled by a StructWalker
+func isSkipped(errs []FieldError, bypassed [][]string, keyChain []string) bool {
+	for _, b := range bypassed {
+		if len(b) <= len(keyChain) && reflect.DeepEqual(b, keyChain[:len(b)]) {
+			return true
+		}
+	}
+
+	return hasFieldError(errs, keyChain)
+}
+
+func validateCrossField(tree *EnvTree, parent reflect.Value, f fieldPlan, chain []string, keyChain []string, opts *populateOptions, errs []FieldError, bypassed [][]string) error {
+	if f.rulesErr != nil {
+		// malformed tags are already reported when field is populated
+		return nil
+	}
+
+	for _, r := range f.rules {
+		if !isCrossFieldRule(r.name) {
+			continue
+		}
+
+		params := strings.SplitN(r.param, " ", 2)
+		sibling, ok := opts.plan(parent.Type()).field(params[0])
+
+		if !ok {
+			return TagError{validationTagName, r.name + "=" + r.param, siblingNotFoundMessage(parent.Type(), params[0])}
+		}
+
+		param := ""
+
+		if len(params) == 2 {
+			param = params[1]
+		}
+
+		siblingKeyChain := appendKey(chain, sibling.tag.resolveKey(tree, chain))
+
+		if isSkipped(errs, bypassed, siblingKeyChain) {
+			continue
+		}
+
+		fieldState := newFieldState(tree, parent.Field(f.index), keyChain, f.tag)
+		siblingState := newFieldState(tree, parent.Field(sibling.index), siblingKeyChain, sibling.tag)
+
+		message, err := crossFieldRuleCheckers[r.name](fieldState, siblingState, param)
+
+		if err != nil {
+			return TagError{validationTagName, r.name + "=" + r.param, err.Error()}
+		}
+
+		if message == "" {
+			continue
+		}
+
+		var value interface{}
+
+		if fieldState.defined {
+			value = fieldState.value.Interface()
+		}
+
+		return ValidationError{value, r.name, r.param, message, keyChain, fieldState.ref.name, fieldState.secret}
+	}
+
+	return nil
+}
+
+// siblingNotFoundMessage explains why a sibling field can't be used, only exported
+// fields declared in the same struct are siblings, a field promoted from an embedded
+// struct lives below the key of the embedded struct
+func siblingNotFoundMessage(typ reflect.Type, name string) string {
+	if f, ok := typ.FieldByName(name); ok && len(f.Index) > 1 {
+		return fmt.Sprintf(`field "%s" is promoted from an embedded struct, it must be declared in the same struct`, name)
+	}
+
+	return fmt.Sprintf(`field "%s" doesn't exist`, name)
+}
+
+func newFieldState(tree *EnvTree, value reflect.Value, keyChain []string, tag fieldTag) fieldState {
+	value, _ = unwrapSecret(value)
+	defined := tag.isDefined(tree, keyChain)
+
+	if value.Kind() == reflect.Struct {
+		defined = tree.IsExistingSubTree(keyChain...)
+	}
+
+	return fieldState{value, defined, tree.ref(keyChain), tag.secret}
+}
+
+func checkRequiredIf(field fieldState, sibling fieldState, param string) (string, error) {
+	if sibling.defined && fmt.Sprint(sibling.value.Interface()) == param && !field.defined {
+		return fmt.Sprintf(`required when variable "%s" is "%s"`, sibling.ref.name, param), nil
+	}
+
+	return "", nil
+}
+
+func checkRequiredWith(field fieldState, sibling fieldState, param string) (string, error) {
+	if sibling.defined && !field.defined {
+		return fmt.Sprintf(`required when variable "%s" is defined`, sibling.ref.name), nil
+	}
+
+	return "", nil
+}
+
+func checkRequiredWithout(field fieldState, sibling fieldState, param string) (string, error) {
+	if !sibling.defined && !field.defined {
+		return fmt.Sprintf(`required when variable "%s" is not defined`, sibling.ref.name), nil
+	}
+
+	return "", nil
+}
+
+func checkExcludedWith(field fieldState, sibling fieldState, param string) (string, error) {
+	if sibling.defined && field.defined {
+		return fmt.Sprintf(`must not be defined when variable "%s" is defined`, sibling.ref.name), nil
+	}
+
+	return "", nil
+}
+
+func compareFields(field fieldState, sibling fieldState, fails func(a float64, b float64) bool, comparison string) (string, error) {
+	for _, f := range []fieldState{field, sibling} {
+		if k := f.value.Kind(); k != reflect.Int && k != reflect.Float32 {
+			return "", fmt.Errorf(`field of type "%s" can't be compared`, k)
+		}
+	}
+
+	if !field.defined || !sibling.defined || !fails(numericValue(field.value), numericValue(sibling.value)) {
+		return "", nil
+	}
+
+	return fmt.Sprintf(`must be %s variable "%s" value (%v)`, comparison, sibling.ref.name, redact(sibling.value.Interface(), sibling.ref.name, sibling.secret)), nil
+}
+
+func checkGtField(field fieldState, sibling fieldState, param string) (string, error) {
+	return compareFields(field, sibling, func(a float64, b float64) bool { return a <= b }, "greater than")
+}
+
+func checkGteField(field fieldState, sibling fieldState, param string) (string, error) {
+	return compareFields(field, sibling, func(a float64, b float64) bool { return a < b }, "greater than or equal to")
+}
+
+func checkLtField(field fieldState, sibling fieldState, param string) (string, error) {
+	return compareFields(field, sibling, func(a float64, b float64) bool { return a >= b }, "lower than")
+}
+
+func checkLteField(field fieldState, sibling fieldState, param string) (string, error) {
+	return compareFields(field, sibling, func(a float64, b float64) bool { return a > b }, "lower than or equal to")
+}
@@ -0,0 +1,219 @@
+package envh
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPopulateStructWithCrossFieldRules(t *testing.T) {
+	type MAILER struct {
+		ENABLED bool
+		HOST    string `validate:"required_if=ENABLED true"`
+		PORT    int    `validate:"required_with=HOST"`
+	}
+
+	type TLS struct {
+		CERTFILE string `validate:"required_without=CERTPEM,excluded_with=CERTPEM"`
+		CERTPEM  string
+	}
+
+	type POOL struct {
+		MIN int `validate:"ltefield=MAX"`
+		MAX int
+	}
+
+	type POPULATESTRUCT struct {
+		MAILER MAILER
+		TLS    TLS
+		POOL   POOL
+	}
+
+	type g struct {
+		init       func()
+		checkError func(err error)
+	}
+
+	tests := []g{
+		{
+			init: func() {
+				setEnv("POPULATESTRUCT_MAILER_ENABLED", "false")
+				setEnv("POPULATESTRUCT_TLS_CERTPEM", "pem")
+				setEnv("POPULATESTRUCT_POOL_MIN", "10")
+				setEnv("POPULATESTRUCT_POOL_MAX", "10")
+			},
+			checkError: func(err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			init: func() {
+				setEnv("POPULATESTRUCT_MAILER_ENABLED", "true")
+				setEnv("POPULATESTRUCT_POOL_MIN", "11")
+				setEnv("POPULATESTRUCT_POOL_MAX", "10")
+			},
+			checkError: func(err error) {
+				assert.EqualError(t, err, `3 error(s) occurred while populating struct :
+  - Field "MAILER.HOST" : Variable "POPULATESTRUCT_MAILER_HOST" is invalid : required when variable "POPULATESTRUCT_MAILER_ENABLED" is "true"
+  - Field "TLS.CERTFILE" : Variable "POPULATESTRUCT_TLS_CERTFILE" is invalid : required when variable "POPULATESTRUCT_TLS_CERTPEM" is not defined
+  - Field "POOL.MIN" : Value "11" of variable "POPULATESTRUCT_POOL_MIN" is invalid : must be lower than or equal to variable "POPULATESTRUCT_POOL_MAX" value (10)`)
+				assert.True(t, errors.Is(err, ErrValidation))
+			},
+		},
+		{
+			init: func() {
+				setEnv("POPULATESTRUCT_MAILER_HOST", "localhost")
+				setEnv("POPULATESTRUCT_TLS_CERTFILE", "/tmp/cert.pem")
+				setEnv("POPULATESTRUCT_TLS_CERTPEM", "pem")
+			},
+			checkError: func(err error) {
+				assert.EqualError(t, err, `2 error(s) occurred while populating struct :
+  - Field "MAILER.PORT" : Variable "POPULATESTRUCT_MAILER_PORT" is invalid : required when variable "POPULATESTRUCT_MAILER_HOST" is defined
+  - Field "TLS.CERTFILE" : Value "/tmp/cert.pem" of variable "POPULATESTRUCT_TLS_CERTFILE" is invalid : must not be defined when variable "POPULATESTRUCT_TLS_CERTPEM" is defined`)
+			},
+		},
+	}
+
+	for _, s := range tests {
+		actual := POPULATESTRUCT{}
+
+		s.init()
+
+		tree, err := NewEnvTree("POPULATESTRUCT", "_")
+
+		assert.NoError(t, err)
+
+		err = tree.Populate(&actual)
+		s.checkError(err)
+		restoreEnvs()
+	}
+}
+
+func TestPopulateStructWithInvalidCrossFieldRules(t *testing.T) {
+	type POPULATESTRUCT struct {
+		TEST1 string `validate:"required_with"`
+		TEST2 string `validate:"required_with=WHATEVER"`
+		TEST3 string `validate:"ltfield=TEST4"`
+		TEST4 int
+	}
+
+	actual := POPULATESTRUCT{}
+
+	tree, err := NewEnvTree("POPULATESTRUCT", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual)
+
+	assert.EqualError(t, err, `3 error(s) occurred while populating struct :
+  - Field "TEST1" : Tag validate:"required_with" is invalid : rule "required_with" requires a field name as parameter
+  - Field "TEST2" : Tag validate:"required_with=WHATEVER" is invalid : field "WHATEVER" doesn't exist
+  - Field "TEST3" : Tag validate:"ltfield=TEST4" is invalid : field of type "string" can't be compared`)
+}
+
+type SKIPCROSSFIELDS struct {
+	MIN  int    `validate:"ltefield=MAX"`
+	MAX  int    `validate:"min=5"`
+	PORT int    `validate:"required_with=HOST"`
+	HOST string `validate:"required_with=PORT"`
+}
+
+func (s *SKIPCROSSFIELDS) Walk(tree *EnvTree, keyChain []string) (bool, error) {
+	if len(keyChain) == 2 && keyChain[1] == "HOST" {
+		s.HOST = "localhost"
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func TestPopulateStructSkipsCrossFieldRulesOfFailedFields(t *testing.T) {
+	type g struct {
+		envs map[string]string
+		err  string
+	}
+
+	tests := []g{
+		{
+			map[string]string{"SKIPCROSSFIELDS_MIN": "whatever", "SKIPCROSSFIELDS_MAX": "10", "SKIPCROSSFIELDS_PORT": "8080"},
+			`1 error(s) occurred while populating struct :
+  - Field "MIN" : Value "whatever" of variable "SKIPCROSSFIELDS_MIN" can't be converted to type "int"`,
+		},
+		{
+			map[string]string{"SKIPCROSSFIELDS_MIN": "3", "SKIPCROSSFIELDS_MAX": "1", "SKIPCROSSFIELDS_PORT": "8080"},
+			`1 error(s) occurred while populating struct :
+  - Field "MAX" : Value "1" of variable "SKIPCROSSFIELDS_MAX" is invalid : must be greater than or equal to 5`,
+		},
+		{
+			map[string]string{"SKIPCROSSFIELDS_MIN": "3", "SKIPCROSSFIELDS_MAX": "whatever", "SKIPCROSSFIELDS_PORT": "8080"},
+			`1 error(s) occurred while populating struct :
+  - Field "MAX" : Value "whatever" of variable "SKIPCROSSFIELDS_MAX" can't be converted to type "int"`,
+		},
+	}
+
+	for _, test := range tests {
+		for k, v := range test.envs {
+			setEnv(k, v)
+		}
+
+		actual := SKIPCROSSFIELDS{}
+
+		tree, err := NewEnvTree("^SKIPCROSSFIELDS", "_")
+
+		assert.NoError(t, err)
+
+		err = tree.PopulateStruct(&actual)
+
+		restoreEnvs()
+
+		assert.EqualError(t, err, test.err)
+		assert.Equal(t, "localhost", actual.HOST, "Must not check rules of a field handled by a StructWalker")
+	}
+}
+
+type PROMOTEDINNER struct {
+	MIN int
+}
+
+func TestPopulateStructWithPromotedSiblingField(t *testing.T) {
+	type PROM struct {
+		PROMOTEDINNER
+		MAX int `validate:"gtfield=MIN"`
+	}
+
+	type PROMP struct {
+		*PROMOTEDINNER
+		HI int `validate:"gtfield=MIN"`
+	}
+
+	setEnv("PROM_PROMOTEDINNER_MIN", "10")
+	setEnv("PROM_MAX", "5")
+	setEnv("PROMP_HI", "5")
+
+	tree, err := NewEnvTree("^PROMP?_", "_")
+
+	assert.NoError(t, err)
+
+	subTree, err := tree.FindSubTree("PROM")
+
+	assert.NoError(t, err)
+
+	err = subTree.Populate(&PROM{})
+
+	assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "MAX" : Tag validate:"gtfield=MIN" is invalid : field "MIN" is promoted from an embedded struct, it must be declared in the same struct`)
+
+	subTree, err = tree.FindSubTree("PROMP")
+
+	assert.NoError(t, err)
+
+	assert.NotPanics(t, func() {
+		err = subTree.Populate(&PROMP{})
+	})
+
+	restoreEnvs()
+
+	assert.Contains(t, err.Error(), `Field "HI" : Tag validate:"gtfield=MIN" is invalid : field "MIN" is promoted from an embedded struct, it must be declared in the same struct`)
+}
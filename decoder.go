@@ -0,0 +1,72 @@
+package envh
+
+import (
+	"reflect"
+	"sync"
+)
+
+var decoders = struct {
+	sync.RWMutex
+	funcs map[reflect.Type]func(value string) (interface{}, error)
+}{funcs: map[reflect.Type]func(value string) (interface{}, error){}}
+
+// RegisterDecoder registers globally a function used to convert a variable to a value of type T,
+// it's used by generic accessors and when a struct is populated, a decoder given
+// with WithDecoder option takes precedence over a registered one
+func RegisterDecoder[T any](decode func(value string) (T, error)) {
+	decoders.Lock()
+	defer decoders.Unlock()
+
+	decoders.funcs[reflect.TypeOf((*T)(nil)).Elem()] = func(value string) (interface{}, error) {
+		return decode(value)
+	}
+}
+
+// decoder returns function used to convert a variable to a value of given type
+func (opts *populateOptions) decoder(typ reflect.Type) (func(value string) (interface{}, error), bool) {
+	if decode, ok := opts.decoders[typ]; ok {
+		return decode, true
+	}
+
+	decoders.RLock()
+	defer decoders.RUnlock()
+
+	decode, ok := decoders.funcs[typ]
+
+	return decode, ok
+}
+
+// decodeValue converts a variable to val type, decoders take precedence
+// over regular conversions, a missing variable is an error only if definition is forced
+func decodeValue(forceDefinition bool, val reflect.Value, lookup func() (string, bool), ref varRef, opts *populateOptions) error {
+	if decode, ok := opts.decoder(val.Type()); ok {
+		return populateWithDecoder(forceDefinition, val, lookup, ref, decode)
+	}
+
+	if inner, ok := unwrapSecret(val); ok {
+		return decodeValue(forceDefinition, inner, lookup, ref, opts)
+	}
+
+	switch val.Type().Kind() {
+	case reflect.Int:
+		return populateInt(forceDefinition, val, lookup, ref)
+	case reflect.Float32:
+		return populateFloat(forceDefinition, val, lookup, ref)
+	case reflect.String:
+		return populateString(forceDefinition, val, lookup, ref)
+	case reflect.Bool:
+		return populateBool(forceDefinition, val, lookup, ref)
+	default:
+		return TypeUnsupported{val.Type().Kind().String(), "int32, float32, string, boolean or struct", ref.keyChain, ref.name}
+	}
+}
+
+// checkDecodable returns a TypeUnsupported error if a variable
+// can't be converted to given type, whether it's defined or not
+func checkDecodable(typ reflect.Type, ref varRef, opts *populateOptions) error {
+	missing := func() (string, bool) {
+		return "", false
+	}
+
+	return decodeValue(false, reflect.New(typ).Elem(), missing, ref, opts)
+}
@@ -0,0 +1,145 @@
+package envh
+
+import (
+	"fmt"
+	"log"
+	"sync"
+)
+
+// Deprecation describes a deprecated variable which was read
+type Deprecation struct {
+	// Variable is name of the deprecated variable
+	Variable string
+	// Replacement is name of the variable to use instead, it can be empty
+	Replacement string
+	// Message gives further details, it can be empty
+	Message string
+}
+
+func (d Deprecation) String() string {
+	s := fmt.Sprintf(`Variable "%s" is deprecated`, d.Variable)
+
+	if d.Replacement != "" {
+		s += fmt.Sprintf(`, use "%s" instead`, d.Replacement)
+	}
+
+	if d.Message != "" {
+		s += " : " + d.Message
+	}
+
+	return s
+}
+
+// DeprecationReporter is notified every time a deprecated variable is read
+// by a getter, and once per variable when a struct is populated
+type DeprecationReporter interface {
+	ReportDeprecation(deprecation Deprecation)
+}
+
+// DeprecationReporterFunc turns a function into a DeprecationReporter
+type DeprecationReporterFunc func(deprecation Deprecation)
+
+// ReportDeprecation calls f(deprecation)
+func (f DeprecationReporterFunc) ReportDeprecation(deprecation Deprecation) {
+	f(deprecation)
+}
+
+type noopReporter struct{}
+
+func (noopReporter) ReportDeprecation(deprecation Deprecation) {}
+
+// NewLogReporter creates a DeprecationReporter writing a line
+// for every deprecated variable read with given logger
+func NewLogReporter(logger *log.Logger) DeprecationReporter {
+	return DeprecationReporterFunc(func(deprecation Deprecation) {
+		logger.Println(deprecation.String())
+	})
+}
+
+var deprecations = struct {
+	sync.RWMutex
+	reporter  DeprecationReporter
+	variables map[string]Deprecation
+}{reporter: noopReporter{}, variables: map[string]Deprecation{}}
+
+// SetDeprecationReporter defines reporter notified when a deprecated variable is read,
+// default reporter does nothing, a nil reporter restores it
+func SetDeprecationReporter(reporter DeprecationReporter) {
+	deprecations.Lock()
+	defer deprecations.Unlock()
+
+	if reporter == nil {
+		reporter = noopReporter{}
+	}
+
+	deprecations.reporter = reporter
+}
+
+// DeprecateVariable marks a variable as deprecated, replacement
+// and message are given to reporter when the variable is read
+func DeprecateVariable(variable string, replacement string, message string) {
+	deprecations.Lock()
+	defer deprecations.Unlock()
+
+	deprecations.variables[variable] = Deprecation{variable, replacement, message}
+}
+
+// deprecationReports holds variables already reported during a population,
+// so a variable is reported at most once, a nil one reports every read
+type deprecationReports map[string]bool
+
+// report notifies reporter unless variable was already reported
+func (r deprecationReports) report(deprecation Deprecation) {
+	if r != nil {
+		if r[deprecation.Variable] {
+			return
+		}
+
+		r[deprecation.Variable] = true
+	}
+
+	reportDeprecation(deprecation)
+}
+
+// reportVariable notifies reporter if
+// a variable was marked as deprecated
+func (r deprecationReports) reportVariable(variable string) {
+	deprecations.RLock()
+	deprecation, ok := deprecations.variables[variable]
+	deprecations.RUnlock()
+
+	if ok {
+		r.report(deprecation)
+	}
+}
+
+func reportDeprecation(deprecation Deprecation) {
+	deprecations.RLock()
+	reporter := deprecations.reporter
+	deprecations.RUnlock()
+
+	reporter.ReportDeprecation(deprecation)
+}
+
+// reportDeprecatedField notifies reporter if a field marked as deprecated is defined,
+// when several candidate keys are declared, only fallback keys are deprecated
+// and first one is given as replacement
+func reportDeprecatedField(tree *EnvTree, chain []string, key string, tag fieldTag, reports deprecationReports) {
+	if !tag.deprecated || len(tag.keys) > 1 && key == tag.keys[0] {
+		return
+	}
+
+	keyChain := appendKey(chain, key)
+
+	if !tree.IsExistingSubTree(keyChain...) {
+		return
+	}
+
+	replacement := ""
+
+	if len(tag.keys) > 1 {
+		replacement = tree.ref(append(append([]string{}, chain...), tag.keys[0])).name
+	}
+
+	reports.report(Deprecation{tree.ref(keyChain).name, replacement, tag.deprecationMessage})
+}
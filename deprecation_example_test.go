@@ -0,0 +1,29 @@
+package envh
+
+import (
+	"fmt"
+	"os"
+)
+
+func ExampleSetDeprecationReporter() {
+	os.Clearenv()
+	setEnv("DB_URL", "postgres://localhost")
+
+	SetDeprecationReporter(DeprecationReporterFunc(func(deprecation Deprecation) {
+		fmt.Println(deprecation)
+	}))
+	DeprecateVariable("DB_URL", "DATABASE_URL", "it will be removed in next major version")
+
+	value, err := NewEnv().GetString("DB_URL")
+
+	if err != nil {
+		return
+	}
+
+	fmt.Println(value)
+
+	SetDeprecationReporter(nil)
+	// Output:
+	// Variable "DB_URL" is deprecated, use "DATABASE_URL" instead : it will be removed in next major version
+	// postgres://localhost
+}
@@ -0,0 +1,15 @@
+//go:build go1.21
+
+package envh
+
+import (
+	"log/slog"
+)
+
+// NewSlogReporter creates a DeprecationReporter logging
+// a warning for every deprecated variable read with given logger
+func NewSlogReporter(logger *slog.Logger) DeprecationReporter {
+	return DeprecationReporterFunc(func(deprecation Deprecation) {
+		logger.Warn("deprecated variable", "variable", deprecation.Variable, "replacement", deprecation.Replacement, "message", deprecation.Message)
+	})
+}
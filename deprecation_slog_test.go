@@ -0,0 +1,34 @@
+//go:build go1.21
+
+package envh
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewSlogReporter(t *testing.T) {
+	setEnv("TEST99_DB_URL", "postgres://localhost")
+
+	buf := bytes.Buffer{}
+	SetDeprecationReporter(NewSlogReporter(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))))
+	DeprecateVariable("TEST99_DB_URL", "TEST99_DATABASE_URL", "removed in v3")
+
+	_ = NewEnv().GetStringUnsecured("TEST99_DB_URL")
+
+	restoreEnvs()
+	resetDeprecations()
+
+	assert.Equal(t, "level=WARN msg=\"deprecated variable\" variable=TEST99_DB_URL replacement=TEST99_DATABASE_URL message=\"removed in v3\"\n", buf.String())
+}
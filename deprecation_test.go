@@ -0,0 +1,194 @@
+package envh
+
+import (
+	"bytes"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func recordDeprecations() *[]Deprecation {
+	reported := []Deprecation{}
+
+	SetDeprecationReporter(DeprecationReporterFunc(func(deprecation Deprecation) {
+		reported = append(reported, deprecation)
+	}))
+
+	return &reported
+}
+
+func resetDeprecations() {
+	SetDeprecationReporter(nil)
+
+	deprecations.Lock()
+	defer deprecations.Unlock()
+
+	deprecations.variables = map[string]Deprecation{}
+}
+
+func TestDeprecationString(t *testing.T) {
+	type test struct {
+		deprecation Deprecation
+		expected    string
+	}
+
+	tests := []test{
+		{Deprecation{"DB_URL", "", ""}, `Variable "DB_URL" is deprecated`},
+		{Deprecation{"DB_URL", "DATABASE_URL", ""}, `Variable "DB_URL" is deprecated, use "DATABASE_URL" instead`},
+		{Deprecation{"DB_URL", "DATABASE_URL", "removed in v3"}, `Variable "DB_URL" is deprecated, use "DATABASE_URL" instead : removed in v3`},
+		{Deprecation{"DB_URL", "", "removed in v3"}, `Variable "DB_URL" is deprecated : removed in v3`},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, test.deprecation.String())
+	}
+}
+
+func TestDeprecateVariableWithGetters(t *testing.T) {
+	setEnv("DEPRECATION_DB_URL", "postgres://localhost")
+	setEnv("DEPRECATION_DB_PORT", "5432")
+
+	reported := recordDeprecations()
+	DeprecateVariable("DEPRECATION_DB_URL", "DEPRECATION_DATABASE_URL", "removed in v3")
+
+	env := NewEnv()
+	tree, err := NewEnvTree("^DEPRECATION", "_")
+
+	assert.NoError(t, err)
+
+	_, err = env.GetString("DEPRECATION_DB_URL")
+	assert.NoError(t, err)
+	_ = env.GetIntUnsecured("DEPRECATION_DB_PORT")
+	_ = env.GetStringUnsecured("DEPRECATION_UNKNOWN")
+	_, err = tree.FindString("DEPRECATION", "DB", "URL")
+	assert.NoError(t, err)
+
+	subTree, err := tree.FindSubTree("DEPRECATION", "DB")
+
+	assert.NoError(t, err)
+
+	_ = subTree.FindStringUnsecured("URL")
+	_ = subTree.FindStringUnsecured("PORT")
+
+	restoreEnvs()
+	resetDeprecations()
+
+	expected := Deprecation{"DEPRECATION_DB_URL", "DEPRECATION_DATABASE_URL", "removed in v3"}
+
+	assert.Equal(t, []Deprecation{expected, expected, expected}, *reported)
+}
+
+func TestDeprecateVariableWithPopulate(t *testing.T) {
+	type DEPRECATION struct {
+		DB struct {
+			URL  string
+			PORT int `default:"5432"`
+		}
+	}
+
+	setEnv("DEPRECATION_DB_URL", "postgres://localhost")
+
+	reported := recordDeprecations()
+	DeprecateVariable("DEPRECATION_DB_URL", "", "")
+	DeprecateVariable("DEPRECATION_DB_PORT", "", "")
+
+	actual := DEPRECATION{}
+
+	tree, err := NewEnvTree("^DEPRECATION", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual)
+
+	restoreEnvs()
+	resetDeprecations()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Deprecation{{"DEPRECATION_DB_URL", "", ""}}, *reported, "Default values must not be reported")
+}
+
+func TestPopulateWithDeprecatedTags(t *testing.T) {
+	type DEPRECATION struct {
+		URL   string `envh:"URL|DSN" deprecated:"removed in v3"`
+		PORT  int    `deprecated:""`
+		DEBUG bool   `deprecated:"not used anymore"`
+		NAME  string `envh:"NAME|TITLE" deprecated:""`
+	}
+
+	setEnv("DEPRECATION_DSN", "postgres://localhost")
+	setEnv("DEPRECATION_PORT", "5432")
+	setEnv("DEPRECATION_NAME", "envh")
+
+	reported := recordDeprecations()
+
+	actual := DEPRECATION{}
+
+	tree, err := NewEnvTree("^DEPRECATION", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual)
+
+	restoreEnvs()
+	resetDeprecations()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Deprecation{
+		{"DEPRECATION_DSN", "DEPRECATION_URL", "removed in v3"},
+		{"DEPRECATION_PORT", "", ""},
+	}, *reported)
+}
+
+func TestPopulateReportsDeprecationsOnce(t *testing.T) {
+	type DEPRECATION struct {
+		URL     string `deprecated:"removed in v3"`
+		STORAGE storage
+	}
+
+	setEnv("DEPRECATION_URL", "postgres://localhost")
+	setEnv("DEPRECATION_STORAGE_TYPE", "s3")
+	setEnv("DEPRECATION_STORAGE_BUCKET", "assets")
+
+	reported := recordDeprecations()
+	DeprecateVariable("DEPRECATION_URL", "DEPRECATION_DSN", "")
+	DeprecateVariable("DEPRECATION_STORAGE_TYPE", "", "")
+
+	actual := DEPRECATION{}
+
+	tree, err := NewEnvTree("^DEPRECATION", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, variantsOption(), WithUnknownKeyPolicy(ReportUnknownKeys))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Deprecation{
+		{"DEPRECATION_URL", "", "removed in v3"},
+		{"DEPRECATION_STORAGE_TYPE", "", ""},
+	}, *reported)
+
+	*reported = []Deprecation{}
+	err = tree.Populate(&actual, variantsOption())
+
+	restoreEnvs()
+	resetDeprecations()
+
+	assert.NoError(t, err)
+	assert.Len(t, *reported, 2, "Must report again on a new population")
+}
+
+func TestNewLogReporter(t *testing.T) {
+	setEnv("TEST99_DB_URL", "postgres://localhost")
+
+	buf := bytes.Buffer{}
+	SetDeprecationReporter(NewLogReporter(log.New(&buf, "", 0)))
+	DeprecateVariable("TEST99_DB_URL", "TEST99_DATABASE_URL", "")
+
+	_ = NewEnv().GetStringUnsecured("TEST99_DB_URL")
+
+	restoreEnvs()
+	resetDeprecations()
+
+	assert.Equal(t, "Variable \"TEST99_DB_URL\" is deprecated, use \"TEST99_DATABASE_URL\" instead\n", buf.String())
+}
@@ -0,0 +1,169 @@
+package envh
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// mapEntryKey stands for any entry key of a map of structs
+const mapEntryKey = "*"
+
+// VariableDescription describes a variable expected by a struct field
+type VariableDescription struct {
+	// Name is the full variable name
+	Name string
+	// KeyChain is key chain of the variable in tree
+	KeyChain []string
+	// Path is Go path of the field, DB.PORT for instance, entries
+	// of a map of structs appear as TENANTS[*].DB.PORT
+	Path string
+	// Type is Go type of the field
+	Type string
+	// Kind is kind of the value converted from variable, a Secret gives
+	// kind of its underlying value and a type converted with a decoder gives reflect.String
+	Kind reflect.Kind
+	// Required is true if population fails when variable is missing
+	Required bool
+	// Default is value used when variable is missing, if HasDefault is true
+	Default    string
+	HasDefault bool
+	// Description is defined in a description struct tag
+	Description string
+	// Secret is true if the field is marked as secret or if variable name is sensitive
+	Secret bool
+	// Aliases are names of fallback variables
+	Aliases []string
+	// Deprecated is true if field is marked as deprecated
+	Deprecated bool
+	// Variant is name of the variant a field belongs to when field lives in an
+	// interface populated with WithVariants, variable is only expected when variant is selected
+	Variant string
+	// Rules are validation rules defined in a validate struct tag
+	Rules string
+}
+
+// Describe returns every variable expected to populate a struct from an EnvTree,
+// full variable names are built joining key chains with delimiter.
+// Structure can be a struct, a pointer to a struct or a struct reflect.Type,
+// options are the ones given to Populate, with WithStrictMode every field without
+// default is required. Map entries keys are unknown without a tree, "*" stands for them
+func Describe(structure interface{}, delimiter string, options ...PopulateOption) ([]VariableDescription, error) {
+	return describe(structure, delimiter, newPopulateOptions(options...))
+}
+
+// DescribeFlat returns every variable expected to populate a struct from an Env
+// with Env.Populate, it behaves like Describe otherwise
+func DescribeFlat(structure interface{}, options ...PopulateOption) ([]VariableDescription, error) {
+	return describe(structure, "", newFlatPopulateOptions(options...))
+}
+
+func describe(structure interface{}, delimiter string, opts *populateOptions) ([]VariableDescription, error) {
+	typ, ok := structure.(reflect.Type)
+
+	if !ok {
+		typ = reflect.TypeOf(structure)
+	}
+
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return []VariableDescription{}, TypeUnsupported{fmt.Sprint(typ), "struct, pointer to struct or struct type", []string{}, ""}
+	}
+
+	descriptions := []VariableDescription{}
+	errs := []FieldError{}
+
+	describeStruct(typ, opts.rootKey(typ), []string{}, "", delimiter, opts, &descriptions, &errs)
+
+	if len(errs) > 0 {
+		return descriptions, PopulateError{errs, []UnknownKeyError{}}
+	}
+
+	return descriptions, nil
+}
+
+func describeStruct(typ reflect.Type, chain []string, path []string, variant string, delimiter string, opts *populateOptions, descriptions *[]VariableDescription, errs *[]FieldError) {
+	for _, f := range opts.plan(typ).fields {
+		field, tag := f.field, f.tag
+		keyChain := appendKey(chain, tag.name)
+		fieldPath := appendKey(path, field.Name)
+
+		if f.tagErr != nil {
+			*errs = append(*errs, FieldError{keyChain, strings.Join(fieldPath, "."), f.tagErr})
+
+			continue
+		}
+
+		set, isVariant := opts.variants[field.Type]
+
+		switch {
+		case isNestedStruct(field.Type, opts):
+			describeStruct(field.Type, opts.structKeyChain(keyChain), fieldPath, variant, delimiter, opts, descriptions, errs)
+		case isStructMap(field.Type, opts):
+			entryType, _ := underlyingStructType(field.Type.Elem())
+			entryPath := appendKey(path, fmt.Sprintf("%s[%s]", field.Name, mapEntryKey))
+
+			describeStruct(entryType, appendKey(keyChain, mapEntryKey), entryPath, variant, delimiter, opts, descriptions, errs)
+		case isVariant:
+			discriminatorTag := tag
+
+			if !opts.flat {
+				discriminatorTag.keys = discriminatorTag.keys[:1]
+			}
+
+			discriminator := newVariableDescription(set.discriminatorKeyChain(keyChain, opts), fieldPath, "string", reflect.String, variant, delimiter, discriminatorTag, field, opts)
+			discriminator.Rules = "oneof=" + strings.Join(set.names(), " ")
+			*descriptions = append(*descriptions, discriminator)
+
+			for _, name := range set.names() {
+				if variantType, ok := underlyingStructType(set.types[name]); ok {
+					describeStruct(variantType, opts.structKeyChain(keyChain), fieldPath, name, delimiter, opts, descriptions, errs)
+				}
+			}
+		default:
+			*descriptions = append(*descriptions, newVariableDescription(keyChain, fieldPath, field.Type.String(), valueKind(field.Type, opts), variant, delimiter, tag, field, opts))
+		}
+	}
+}
+
+func newVariableDescription(keyChain []string, path []string, typ string, kind reflect.Kind, variant string, delimiter string, tag fieldTag, field reflect.StructField, opts *populateOptions) VariableDescription {
+	name := strings.Join(keyChain, delimiter)
+	aliases := []string{}
+
+	for _, key := range tag.keys[1:] {
+		aliases = append(aliases, strings.Join(append(append([]string{}, keyChain[:len(keyChain)-1]...), key), delimiter))
+	}
+
+	return VariableDescription{
+		Name:        name,
+		KeyChain:    keyChain,
+		Path:        strings.Join(path, "."),
+		Type:        typ,
+		Kind:        kind,
+		Required:    opts.forceDefinition && !tag.hasDefault,
+		Default:     tag.defaultValue,
+		HasDefault:  tag.hasDefault,
+		Description: field.Tag.Get(descriptionTagName),
+		Secret:      isSecret(name, tag.secret),
+		Aliases:     aliases,
+		Deprecated:  tag.deprecated,
+		Variant:     variant,
+		Rules:       field.Tag.Get(validationTagName),
+	}
+}
+
+// valueKind returns kind of the value converted from a variable
+func valueKind(typ reflect.Type, opts *populateOptions) reflect.Kind {
+	if _, ok := opts.decoder(typ); ok {
+		return reflect.String
+	}
+
+	if inner, ok := unwrapSecret(reflect.New(typ).Elem()); ok {
+		return valueKind(inner.Type(), opts)
+	}
+
+	return typ.Kind()
+}
@@ -0,0 +1,31 @@
+package envh
+
+import (
+	"fmt"
+)
+
+func ExampleDescribe() {
+	type DB struct {
+		HOST string `description:"database host"`
+		PORT int    `default:"5432" description:"database port"`
+	}
+
+	type APP struct {
+		DB       DB
+		PASSWORD string
+	}
+
+	descriptions, err := Describe(APP{}, "_", WithStrictMode())
+
+	if err != nil {
+		return
+	}
+
+	for _, d := range descriptions {
+		fmt.Printf("%s %s required=%t default=%q secret=%t %s\n", d.Name, d.Type, d.Required, d.Default, d.Secret, d.Description)
+	}
+	// Output:
+	// APP_DB_HOST string required=true default="" secret=false database host
+	// APP_DB_PORT int required=false default="5432" secret=false database port
+	// APP_PASSWORD string required=true default="" secret=true
+}
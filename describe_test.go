@@ -0,0 +1,93 @@
+package envh
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type DESCRIBE struct {
+	DB struct {
+		URL      string `envh:"URL|DSN" validate:"url" description:"database url"`
+		PORT     int    `default:"5432"`
+		PASSWORD string
+	}
+	TENANTS map[string]struct {
+		NAME string `deprecated:""`
+	}
+	STORAGE storage
+	TIMEOUT time.Duration
+	TOKEN   Secret[string]
+	private string
+}
+
+func TestDescribe(t *testing.T) {
+	descriptions, err := Describe(&DESCRIBE{}, "_", WithStrictMode(), variantsOption(), WithDecoder(time.ParseDuration))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []VariableDescription{
+		{Name: "DESCRIBE_DB_URL", KeyChain: []string{"DESCRIBE", "DB", "URL"}, Path: "DB.URL", Type: "string", Kind: reflect.String, Required: true, Description: "database url", Aliases: []string{"DESCRIBE_DB_DSN"}, Rules: "url"},
+		{Name: "DESCRIBE_DB_PORT", KeyChain: []string{"DESCRIBE", "DB", "PORT"}, Path: "DB.PORT", Type: "int", Kind: reflect.Int, Default: "5432", HasDefault: true, Aliases: []string{}},
+		{Name: "DESCRIBE_DB_PASSWORD", KeyChain: []string{"DESCRIBE", "DB", "PASSWORD"}, Path: "DB.PASSWORD", Type: "string", Kind: reflect.String, Required: true, Secret: true, Aliases: []string{}},
+		{Name: "DESCRIBE_TENANTS_*_NAME", KeyChain: []string{"DESCRIBE", "TENANTS", "*", "NAME"}, Path: "TENANTS[*].NAME", Type: "string", Kind: reflect.String, Required: true, Aliases: []string{}, Deprecated: true},
+		{Name: "DESCRIBE_STORAGE_TYPE", KeyChain: []string{"DESCRIBE", "STORAGE", "TYPE"}, Path: "STORAGE", Type: "string", Kind: reflect.String, Required: true, Aliases: []string{}, Rules: "oneof=fs s3"},
+		{Name: "DESCRIBE_STORAGE_PATH", KeyChain: []string{"DESCRIBE", "STORAGE", "PATH"}, Path: "STORAGE.PATH", Type: "string", Kind: reflect.String, Required: true, Aliases: []string{}, Variant: "fs"},
+		{Name: "DESCRIBE_STORAGE_BUCKET", KeyChain: []string{"DESCRIBE", "STORAGE", "BUCKET"}, Path: "STORAGE.BUCKET", Type: "string", Kind: reflect.String, Required: true, Aliases: []string{}, Variant: "s3", Rules: "nonempty"},
+		{Name: "DESCRIBE_STORAGE_REGION", KeyChain: []string{"DESCRIBE", "STORAGE", "REGION"}, Path: "STORAGE.REGION", Type: "string", Kind: reflect.String, Default: "eu-west-1", HasDefault: true, Aliases: []string{}, Variant: "s3"},
+		{Name: "DESCRIBE_TIMEOUT", KeyChain: []string{"DESCRIBE", "TIMEOUT"}, Path: "TIMEOUT", Type: "time.Duration", Kind: reflect.String, Required: true, Aliases: []string{}},
+		{Name: "DESCRIBE_TOKEN", KeyChain: []string{"DESCRIBE", "TOKEN"}, Path: "TOKEN", Type: "envh.Secret[string]", Kind: reflect.String, Required: true, Secret: true, Aliases: []string{}},
+	}, descriptions)
+}
+
+func TestDescribeWithRootKeyAndType(t *testing.T) {
+	type Server struct {
+		Host string
+		Port int
+	}
+
+	descriptions, err := Describe(reflect.TypeOf(Server{}), ".", WithRootKey("APP", "SERVER"), WithNamingStrategy(UpperCaseNaming))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []VariableDescription{
+		{Name: "APP.SERVER.HOST", KeyChain: []string{"APP", "SERVER", "HOST"}, Path: "Host", Type: "string", Kind: reflect.String, Aliases: []string{}},
+		{Name: "APP.SERVER.PORT", KeyChain: []string{"APP", "SERVER", "PORT"}, Path: "Port", Type: "int", Kind: reflect.Int, Aliases: []string{}},
+	}, descriptions)
+}
+
+func TestDescribeFlat(t *testing.T) {
+	type CONFIG struct {
+		Database struct {
+			URL string `env:"DATABASE_URL|DB_URL"`
+		}
+		Port int `env:"PORT" default:"8080"`
+	}
+
+	descriptions, err := DescribeFlat(CONFIG{})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []VariableDescription{
+		{Name: "DATABASE_URL", KeyChain: []string{"DATABASE_URL"}, Path: "Database.URL", Type: "string", Kind: reflect.String, Aliases: []string{"DB_URL"}},
+		{Name: "PORT", KeyChain: []string{"PORT"}, Path: "Port", Type: "int", Kind: reflect.Int, Default: "8080", HasDefault: true, Aliases: []string{}},
+	}, descriptions)
+}
+
+func TestDescribeWithErrors(t *testing.T) {
+	type CONFIG struct {
+		Port int `envh:",whatever"`
+	}
+
+	_, err := Describe(&CONFIG{}, "_")
+
+	assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "Port" : Tag envh:",whatever" is invalid : option "whatever" doesn't exist`)
+
+	_, err = Describe(1, "_")
+
+	assert.EqualError(t, err, `Type "int" is not supported : you must provide "struct, pointer to struct or struct type"`)
+
+	_, err = Describe(nil, "_")
+
+	assert.EqualError(t, err, `Type "<nil>" is not supported : you must provide "struct, pointer to struct or struct type"`)
+}
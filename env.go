@@ -42,11 +42,7 @@ func (e Env) GetAllKeys() []string {
 // GetString returns a string if variable exists
 // or an error otherwise
 func (e Env) GetString(key string) (string, error) {
-	return getString(func() (string, bool) {
-		v, ok := (*e.envs)[key]
-
-		return v, ok
-	})
+	return getString(e.lookup(key), varRef{name: key})
 }
 
 // GetStringUnsecured is insecured version of GetString to avoid the burden
@@ -54,11 +50,7 @@ func (e Env) GetString(key string) (string, error) {
 // the variable is missing, it returns default zero string value.
 // This function has to be used carefully
 func (e Env) GetStringUnsecured(key string) string {
-	if val, err := getString(func() (string, bool) {
-		v, ok := (*e.envs)[key]
-
-		return v, ok
-	}); err == nil {
+	if val, err := getString(e.lookup(key), varRef{name: key}); err == nil {
 		return val
 	}
 
@@ -68,11 +60,7 @@ func (e Env) GetStringUnsecured(key string) string {
 // GetInt returns an integer if variable exists
 // or an error if value is not an integer or doesn't exist
 func (e Env) GetInt(key string) (int, error) {
-	return getInt(func() (string, bool) {
-		v, ok := (*e.envs)[key]
-
-		return v, ok
-	})
+	return getInt(e.lookup(key), varRef{name: key})
 }
 
 // GetIntUnsecured is insecured version of GetInt to avoid the burden
@@ -80,11 +68,7 @@ func (e Env) GetInt(key string) (int, error) {
 // the variable is missing or not an int value, it returns default zero int value.
 // This function has to be used carefully
 func (e Env) GetIntUnsecured(key string) int {
-	if val, err := getInt(func() (string, bool) {
-		v, ok := (*e.envs)[key]
-
-		return v, ok
-	}); err == nil {
+	if val, err := getInt(e.lookup(key), varRef{name: key}); err == nil {
 		return val
 	}
 
@@ -94,11 +78,7 @@ func (e Env) GetIntUnsecured(key string) int {
 // GetFloat returns a float if variable exists
 // or an error if value is not a float or doesn't exist
 func (e Env) GetFloat(key string) (float32, error) {
-	return getFloat(func() (string, bool) {
-		v, ok := (*e.envs)[key]
-
-		return v, ok
-	})
+	return getFloat(e.lookup(key), varRef{name: key})
 }
 
 // GetFloatUnsecured is insecured version of GetFloat to avoid the burden
@@ -106,11 +86,7 @@ func (e Env) GetFloat(key string) (float32, error) {
 // the variable is missing or not a floating value, it returns default zero floating value.
 // This function has to be used carefully
 func (e Env) GetFloatUnsecured(key string) float32 {
-	if val, err := getFloat(func() (string, bool) {
-		v, ok := (*e.envs)[key]
-
-		return v, ok
-	}); err == nil {
+	if val, err := getFloat(e.lookup(key), varRef{name: key}); err == nil {
 		return val
 	}
 
@@ -120,11 +96,7 @@ func (e Env) GetFloatUnsecured(key string) float32 {
 // GetBool returns a boolean if variable exists
 // or an error if value is not a boolean or doesn't exist
 func (e Env) GetBool(key string) (bool, error) {
-	return getBool(func() (string, bool) {
-		v, ok := (*e.envs)[key]
-
-		return v, ok
-	})
+	return getBool(e.lookup(key), varRef{name: key})
 }
 
 // GetBoolUnsecured is insecured version of GetBool to avoid the burden
@@ -132,11 +104,7 @@ func (e Env) GetBool(key string) (bool, error) {
 // the variable is missing or not a boolean value, it returns default zero boolean value.
 // This function has to be used carefully
 func (e Env) GetBoolUnsecured(key string) bool {
-	if val, err := getBool(func() (string, bool) {
-		v, ok := (*e.envs)[key]
-
-		return v, ok
-	}); err == nil {
+	if val, err := getBool(e.lookup(key), varRef{name: key}); err == nil {
 		return val
 	}
 
@@ -184,3 +152,47 @@ func (e Env) FindEntriesUnsecured(reg string) map[string]string {
 
 	return results
 }
+
+// lookup returns a function giving value of a variable,
+// reading a deprecated variable reports it
+func (e Env) lookup(key string) func() (string, bool) {
+	return func() (string, bool) {
+		v, ok := (*e.envs)[key]
+
+		if ok {
+			deprecationReports(nil).reportVariable(key)
+		}
+
+		return v, ok
+	}
+}
+
+// Populate fills a structure with environment variables, a field is matched
+// against a full variable name defined in an env struct tag (env:"DATABASE_URL"),
+// field name is used otherwise. Nested structs don't add any key,
+// so variables don't have to follow a hierarchical delimiter scheme.
+// Population shares behaviour of EnvTree.Populate : options, decoders, secret fields,
+// default values, validate rules and hooks, except that WithRootKey and
+// WithUnknownKeyPolicy have no effect, tree given to hooks and walkers
+// holds every variable as a root key.
+func (e Env) Populate(structure interface{}, options ...PopulateOption) error {
+	opts := newFlatPopulateOptions(options...)
+	tree := e.tree()
+
+	return populate(structure, &tree, opts)
+}
+
+// tree returns a tree where every variable is a root key
+func (e Env) tree() EnvTree {
+	root := newNode()
+
+	for k, v := range *e.envs {
+		child := newNode()
+		child.key = k
+		child.value = v
+		child.hasValue = true
+		root.appendNode(child)
+	}
+
+	return EnvTree{root, "", []string{}}
+}
@@ -68,7 +68,7 @@ func ExampleEnv_GetInt() {
 
 	// Output:
 	// 1 <nil>
-	// 0 Value "TEST" can't be converted to type "int"
+	// 0 Value "TEST" of variable "STRING" can't be converted to type "int"
 }
 
 func ExampleEnv_GetIntUnsecured() {
@@ -101,7 +101,7 @@ func ExampleEnv_GetFloat() {
 
 	// Output:
 	// 1.1 <nil>
-	// 0 Value "TEST" can't be converted to type "float"
+	// 0 Value "TEST" of variable "STRING" can't be converted to type "float"
 }
 
 func ExampleEnv_GetFloatUnsecured() {
@@ -131,7 +131,7 @@ func ExampleEnv_GetBool() {
 
 	// Output:
 	// true <nil>
-	// false Value "TEST" can't be converted to type "bool"
+	// false Value "TEST" of variable "STRING" can't be converted to type "bool"
 }
 
 func ExampleEnv_GetBoolUnsecured() {
@@ -187,3 +187,25 @@ func ExampleEnv_FindEntriesUnsecured() {
 	// API -> PASSWORD = password, API -> USERNAME = password
 	// map[]
 }
+
+func ExampleEnv_Populate() {
+	type Config struct {
+		DatabaseURL string `env:"DATABASE_URL" validate:"url"`
+		Port        int    `env:"PORT" default:"8080"`
+		Debug       bool   `env:"APP_DEBUG"`
+	}
+
+	os.Clearenv()
+	setEnv("DATABASE_URL", "postgres://localhost/app")
+	setEnv("APP_DEBUG", "true")
+
+	config := Config{}
+
+	if err := NewEnv().Populate(&config, WithStrictMode()); err != nil {
+		return
+	}
+
+	fmt.Printf("%+v\n", config)
+	// Output:
+	// {DatabaseURL:postgres://localhost/app Port:8080 Debug:true}
+}
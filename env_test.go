@@ -1,8 +1,10 @@
 package envh
 
 import (
+	"errors"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
@@ -96,7 +98,7 @@ func TestGetString(t *testing.T) {
 
 	value, err = q.GetString("TEST100")
 
-	assert.EqualError(t, err, "Variable not found", "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Variable "TEST100" not found`, "Must return an error when variable can't be found")
 	assert.Equal(t, "", value, "Must return empty string")
 }
 
@@ -130,12 +132,12 @@ func TestGetInt(t *testing.T) {
 
 	value, err = q.GetInt("TEST100")
 
-	assert.EqualError(t, err, "Variable not found", "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Variable "TEST100" not found`, "Must return an error when variable can't be found")
 	assert.Equal(t, 0, value, "Must return value")
 
 	value, err = q.GetInt("TEST1")
 
-	assert.EqualError(t, err, `Value "test1" can't be converted to type "int"`, "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Value "test1" of variable "TEST1" can't be converted to type "int"`, "Must return an error when variable can't be found")
 	assert.Equal(t, 0, value, "Must return empty string")
 }
 
@@ -177,12 +179,12 @@ func TestGetBool(t *testing.T) {
 
 	value, err = q.GetBool("TEST100")
 
-	assert.EqualError(t, err, "Variable not found", "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Variable "TEST100" not found`, "Must return an error when variable can't be found")
 	assert.Equal(t, false, value, "Must return value")
 
 	value, err = q.GetBool("TEST1")
 
-	assert.EqualError(t, err, `Value "test1" can't be converted to type "bool"`, "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Value "test1" of variable "TEST1" can't be converted to type "bool"`, "Must return an error when variable can't be found")
 	assert.Equal(t, false, value, "Must return empty string")
 }
 
@@ -224,12 +226,12 @@ func TestGetFloat(t *testing.T) {
 
 	value, err = q.GetFloat("TEST100")
 
-	assert.EqualError(t, err, "Variable not found", "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Variable "TEST100" not found`, "Must return an error when variable can't be found")
 	assert.Equal(t, float32(0), value, "Must return value")
 
 	value, err = q.GetFloat("TEST1")
 
-	assert.EqualError(t, err, `Value "test1" can't be converted to type "float"`, "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Value "test1" of variable "TEST1" can't be converted to type "float"`, "Must return an error when variable can't be found")
 	assert.Equal(t, float32(0), value, "Must return empty string")
 }
 
@@ -254,3 +256,62 @@ func TestGetFloatUnsecured(t *testing.T) {
 
 	assert.Equal(t, float32(0), value, "Must return empty string")
 }
+
+func TestPopulate(t *testing.T) {
+	type DATABASE struct {
+		URL      string `env:"TEST99_DATABASE_URL" validate:"url"`
+		PASSWORD string `env:"TEST99_DB_PASS,secret"`
+	}
+
+	type CONFIG struct {
+		Database DATABASE
+		Port     int           `env:"TEST99_PORT" default:"8080"`
+		Timeout  time.Duration `env:"TEST99_TIMEOUT" default:"10s"`
+		Debug    bool          `env:"TEST99_DEBUG"`
+	}
+
+	setEnv("TEST99_DATABASE_URL", "postgres://localhost/app")
+	setEnv("TEST99_DB_PASS", "hunter2")
+	setEnv("TEST99_DEBUG", "true")
+
+	actual := CONFIG{}
+
+	err := NewEnv().Populate(&actual, WithDecoder(time.ParseDuration))
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, CONFIG{DATABASE{"postgres://localhost/app", "hunter2"}, 8080, 10 * time.Second, true}, actual)
+}
+
+func TestPopulateWithErrors(t *testing.T) {
+	type CONFIG struct {
+		URL      string `env:"TEST99_DATABASE_URL" validate:"url"`
+		PASSWORD int    `env:"TEST99_DB_PASS,secret"`
+		PORT     int    `env:"TEST99_PORT"`
+		HOST     string `env:"TEST99_HOST" validate:"required_with=PORT"`
+	}
+
+	setEnv("TEST99_DATABASE_URL", "localhost")
+	setEnv("TEST99_DB_PASS", "hunter2")
+	setEnv("TEST99_PORT", "8080")
+
+	actual := CONFIG{}
+
+	err := NewEnv().Populate(&actual, WithStrictMode())
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `3 error(s) occurred while populating struct :
+  - Field "URL" : Value "localhost" of variable "TEST99_DATABASE_URL" is invalid : must be a valid URL
+  - Field "PASSWORD" : Value "******" of variable "TEST99_DB_PASS" can't be converted to type "int"
+  - Field "HOST" : Variable "TEST99_HOST" not found`)
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Equal(t, 8080, actual.PORT)
+}
+
+func TestPopulateWithWrongType(t *testing.T) {
+	err := NewEnv().Populate(struct{}{})
+
+	assert.EqualError(t, err, `Type "struct" is not supported : you must provide "pointer to struct"`)
+}
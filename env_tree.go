@@ -9,7 +9,9 @@ import (
 // to store a config the same way as in a yaml file or whatever
 // format allows to store a config hierarchically
 type EnvTree struct {
-	root *node
+	root      *node
+	delimiter string
+	path      []string
 }
 
 // NewEnvTree creates an environment variable tree.
@@ -24,13 +26,13 @@ func NewEnvTree(reg string, delimiter string) (EnvTree, error) {
 
 	t := createTreeFromDelimiterFilteringByRegexp(r, delimiter)
 
-	return EnvTree{t}, nil
+	return EnvTree{t, delimiter, []string{}}, nil
 }
 
 // FindString returns a string if key chain exists
 // or an error otherwise
 func (e EnvTree) FindString(keyChain ...string) (string, error) {
-	return getString(getNodeValueByKeyChain(e.root, &keyChain))
+	return getString(e.lookup(keyChain), e.ref(keyChain))
 }
 
 // FindStringUnsecured is insecured version of FindString to avoid the burden
@@ -38,7 +40,7 @@ func (e EnvTree) FindString(keyChain ...string) (string, error) {
 // the variable is missing, it returns default zero string value.
 // This function has to be used carefully
 func (e EnvTree) FindStringUnsecured(keyChain ...string) string {
-	if val, err := getString(getNodeValueByKeyChain(e.root, &keyChain)); err == nil {
+	if val, err := getString(e.lookup(keyChain), e.ref(keyChain)); err == nil {
 		return val
 	}
 
@@ -48,7 +50,7 @@ func (e EnvTree) FindStringUnsecured(keyChain ...string) string {
 // FindInt returns an integer if key chain exists
 // or an error if value is not an integer or doesn't exist
 func (e EnvTree) FindInt(keyChain ...string) (int, error) {
-	return getInt(getNodeValueByKeyChain(e.root, &keyChain))
+	return getInt(e.lookup(keyChain), e.ref(keyChain))
 }
 
 // FindIntUnsecured is insecured version of FindInt to avoid the burden
@@ -56,7 +58,7 @@ func (e EnvTree) FindInt(keyChain ...string) (int, error) {
 // the variable is missing or not an int value, it returns default zero int value.
 // This function has to be used carefully
 func (e EnvTree) FindIntUnsecured(keyChain ...string) int {
-	if val, err := getInt(getNodeValueByKeyChain(e.root, &keyChain)); err == nil {
+	if val, err := getInt(e.lookup(keyChain), e.ref(keyChain)); err == nil {
 		return val
 	}
 
@@ -66,7 +68,7 @@ func (e EnvTree) FindIntUnsecured(keyChain ...string) int {
 // FindFloat returns a float if key chain exists
 // or an error if value is not a float or doesn't exist
 func (e EnvTree) FindFloat(keyChain ...string) (float32, error) {
-	return getFloat(getNodeValueByKeyChain(e.root, &keyChain))
+	return getFloat(e.lookup(keyChain), e.ref(keyChain))
 }
 
 // FindFloatUnsecured is insecured version of FindFloat to avoid the burden
@@ -74,7 +76,7 @@ func (e EnvTree) FindFloat(keyChain ...string) (float32, error) {
 // the variable is missing or not a floating value, it returns default zero floating value.
 // This function has to be used carefully
 func (e EnvTree) FindFloatUnsecured(keyChain ...string) float32 {
-	if val, err := getFloat(getNodeValueByKeyChain(e.root, &keyChain)); err == nil {
+	if val, err := getFloat(e.lookup(keyChain), e.ref(keyChain)); err == nil {
 		return val
 	}
 
@@ -84,7 +86,7 @@ func (e EnvTree) FindFloatUnsecured(keyChain ...string) float32 {
 // FindBool returns a boolean if key chain exists
 // or an error if value is not a boolean or doesn't exist
 func (e EnvTree) FindBool(keyChain ...string) (bool, error) {
-	return getBool(getNodeValueByKeyChain(e.root, &keyChain))
+	return getBool(e.lookup(keyChain), e.ref(keyChain))
 }
 
 // FindBoolUnsecured is insecured version of FindBool to avoid the burden
@@ -92,7 +94,7 @@ func (e EnvTree) FindBool(keyChain ...string) (bool, error) {
 // the variable is missing or not a boolean value, it returns default zero boolean value.
 // This function has to be used carefully
 func (e EnvTree) FindBoolUnsecured(keyChain ...string) bool {
-	if val, err := getBool(getNodeValueByKeyChain(e.root, &keyChain)); err == nil {
+	if val, err := getBool(e.lookup(keyChain), e.ref(keyChain)); err == nil {
 		return val
 	}
 
@@ -113,7 +115,7 @@ func (e EnvTree) HasSubTreeValue(keyChain ...string) (bool, error) {
 	n, exists := e.root.findNodeByKeyChain(&keyChain)
 
 	if !exists {
-		return false, NodeNotFoundError{keyChain}
+		return false, NodeNotFoundError{keyChain, e.ref(keyChain).name}
 	}
 
 	return n.hasValue, nil
@@ -140,10 +142,10 @@ func (e EnvTree) HasSubTreeValueUnsecured(keyChain ...string) bool {
 // second value
 func (e EnvTree) FindSubTree(keyChain ...string) (EnvTree, error) {
 	if n, exists := e.root.findNodeByKeyChain(&keyChain); exists {
-		return EnvTree{n}, nil
+		return e.subTree(n, keyChain), nil
 	}
 
-	return EnvTree{}, NodeNotFoundError{keyChain}
+	return EnvTree{}, NodeNotFoundError{keyChain, e.ref(keyChain).name}
 }
 
 // FindSubTreeUnsecured is insecured version of FindSubTree to avoid the burden
@@ -152,7 +154,7 @@ func (e EnvTree) FindSubTree(keyChain ...string) (EnvTree, error) {
 // This function has to be used carefully
 func (e EnvTree) FindSubTreeUnsecured(keyChain ...string) EnvTree {
 	if n, exists := e.root.findNodeByKeyChain(&keyChain); exists {
-		return EnvTree{n}
+		return e.subTree(n, keyChain)
 	}
 
 	return EnvTree{}
@@ -165,7 +167,7 @@ func (e EnvTree) FindChildrenKeys(keyChain ...string) ([]string, error) {
 	n, exists := e.root.findNodeByKeyChain(&keyChain)
 
 	if !exists {
-		return []string{}, NodeNotFoundError{keyChain}
+		return []string{}, NodeNotFoundError{keyChain, e.ref(keyChain).name}
 	}
 
 	keys := []string{}
@@ -211,7 +213,7 @@ func (e EnvTree) GetChildrenKeys() []string {
 // GetString returns current tree value as string if value exists
 // or an error as second parameter
 func (e EnvTree) GetString() (string, error) {
-	return getString(getRootValue(e))
+	return getString(getRootValue(e), e.ref([]string{}))
 }
 
 // GetStringUnsecured is insecured version of GetString to avoid the burden
@@ -219,7 +221,7 @@ func (e EnvTree) GetString() (string, error) {
 // the variable is missing, it returns default zero string value.
 // This function has to be used carefully
 func (e EnvTree) GetStringUnsecured() string {
-	if val, err := getString(getRootValue(e)); err == nil {
+	if val, err := getString(getRootValue(e), e.ref([]string{})); err == nil {
 		return val
 	}
 
@@ -229,7 +231,7 @@ func (e EnvTree) GetStringUnsecured() string {
 // GetInt returns current tree value as int if value exists
 // or an error if value is not an integer or doesn't exist
 func (e EnvTree) GetInt() (int, error) {
-	return getInt(getRootValue(e))
+	return getInt(getRootValue(e), e.ref([]string{}))
 }
 
 // GetIntUnsecured is insecured version of GetInt to avoid the burden
@@ -237,7 +239,7 @@ func (e EnvTree) GetInt() (int, error) {
 // the variable is missing or not an int value, it returns default zero int value.
 // This function has to be used carefully
 func (e EnvTree) GetIntUnsecured() int {
-	if val, err := getInt(getRootValue(e)); err == nil {
+	if val, err := getInt(getRootValue(e), e.ref([]string{})); err == nil {
 		return val
 	}
 
@@ -247,7 +249,7 @@ func (e EnvTree) GetIntUnsecured() int {
 // GetFloat returns current tree value as float if value exists
 // or an error if value is not a float or doesn't exist
 func (e EnvTree) GetFloat() (float32, error) {
-	return getFloat(getRootValue(e))
+	return getFloat(getRootValue(e), e.ref([]string{}))
 }
 
 // GetFloatUnsecured is insecured version of GetFloat to avoid the burden
@@ -255,7 +257,7 @@ func (e EnvTree) GetFloat() (float32, error) {
 // the variable is missing or not a floating value, it returns default zero floating value.
 // This function has to be used carefully
 func (e EnvTree) GetFloatUnsecured() float32 {
-	if val, err := getFloat(getRootValue(e)); err == nil {
+	if val, err := getFloat(getRootValue(e), e.ref([]string{})); err == nil {
 		return val
 	}
 
@@ -265,7 +267,7 @@ func (e EnvTree) GetFloatUnsecured() float32 {
 // GetBool returns current tree value as boolean if value exists
 // or an error if value is not a boolean or doesn't exist
 func (e EnvTree) GetBool() (bool, error) {
-	return getBool(getRootValue(e))
+	return getBool(getRootValue(e), e.ref([]string{}))
 }
 
 // GetBoolUnsecured is insecured version of GetBool to avoid the burden
@@ -273,7 +275,7 @@ func (e EnvTree) GetBool() (bool, error) {
 // the variable is missing or not a boolean value, it returns default zero boolean value.
 // This function has to be used carefully
 func (e EnvTree) GetBoolUnsecured() bool {
-	if val, err := getBool(getRootValue(e)); err == nil {
+	if val, err := getBool(getRootValue(e), e.ref([]string{})); err == nil {
 		return val
 	}
 
@@ -296,8 +298,9 @@ func (e EnvTree) GetKey() string {
 // It's possible to control the way struct fields are defined
 // implementing StructWalker interface on structure,
 // checkout StructWalker documentation for further examples.
+// Supported struct tags are described in Populate documentation.
 func (e EnvTree) PopulateStruct(structure interface{}) error {
-	return populateStructFromEnvTree(structure, &e, false)
+	return e.Populate(structure)
 }
 
 // PopulateStructWithStrictMode fills a structure with datas extracted.
@@ -305,8 +308,98 @@ func (e EnvTree) PopulateStruct(structure interface{}) error {
 // It's possible to control the way struct fields are defined
 // implementing StructWalker interface on structure,
 // checkout StructWalker documentation for further examples.
+// Supported struct tags are described in Populate documentation.
 func (e EnvTree) PopulateStructWithStrictMode(structure interface{}) error {
-	return populateStructFromEnvTree(structure, &e, true)
+	return e.Populate(structure, WithStrictMode())
+}
+
+// PopulateStructAt fills a structure with datas extracted from node matching
+// key chain instead of using struct type name as first key, so a same type can be
+// populated from several sub trees and anonymous types can be used.
+// An empty key chain means current tree root is the struct itself.
+// Apart from that, it behaves like PopulateStruct
+func (e EnvTree) PopulateStructAt(structure interface{}, keyChain ...string) error {
+	return e.Populate(structure, WithRootKey(keyChain...))
+}
+
+// PopulateStructWithStrictKeys fills a structure with datas extracted
+// like PopulateStruct does, but every variable defined below the struct key
+// which doesn't match any field (a typo for instance) is reported as well,
+// with the closest expected variable name as suggestion.
+// Sub trees of fields handled by a StructWalker are never reported.
+func (e EnvTree) PopulateStructWithStrictKeys(structure interface{}) error {
+	return e.Populate(structure, WithUnknownKeyPolicy(ReportUnknownKeys))
+}
+
+// Populate fills a structure with datas extracted, behaviour is
+// configured with options : strict mode, tag name, naming strategy, root key,
+// custom decoders, unknown keys policy, walkers and hooks.
+// Without any option it behaves like PopulateStruct,
+// other PopulateStruct* functions are shortcuts for a given set of options.
+// Population doesn't stop at the first failing field, every error
+// encountered is gathered in a PopulateError.
+//
+// Key matching a field can be overridden with an envh struct tag (envh:"NAME"),
+// several candidate keys can be separated by pipes (envh:"NAME|OLD_NAME"), first one defined wins,
+// values of fields marked as secret (envh:",secret") or whose variable name is
+// sensitive (see IsSensitiveKey) are redacted from errors.
+// A value used when a variable is missing can be defined in a default struct tag (default:"8080").
+// Defined values can be checked with rules declared in a validate struct tag,
+// rules are separated by commas : min=N, max=N (bounds of a number or of a string length),
+// len=N, oneof=a b c, regex=EXPR, url, hostname, ip, port and nonempty.
+// Rules involving a field declared in the same struct are checked once whole struct is populated :
+// required_if=FIELD VALUE, required_with=FIELD, required_without=FIELD, excluded_with=FIELD,
+// gtfield=FIELD, gtefield=FIELD, ltfield=FIELD and ltefield=FIELD.
+// Once every field is set, StructFinalizer and StructValidator
+// are called on structs implementing them.
+func (e EnvTree) Populate(structure interface{}, options ...PopulateOption) error {
+	return populate(structure, &e, newPopulateOptions(options...))
+}
+
+// findNode returns node matching key chain,
+// an empty key chain gives current tree root
+func (e EnvTree) findNode(keyChain []string) (*node, bool) {
+	if len(keyChain) == 0 {
+		return e.root, true
+	}
+
+	return e.root.findNodeByKeyChain(&keyChain)
+}
+
+func (e EnvTree) subTree(n *node, keyChain []string) EnvTree {
+	return EnvTree{n, e.delimiter, append(append([]string{}, e.path...), keyChain...)}
+}
+
+// lookup returns a function giving value of key chain,
+// reading a deprecated variable reports it
+func (e EnvTree) lookup(keyChain []string) func() (string, bool) {
+	return e.reportingLookup(keyChain, nil)
+}
+
+// reportingLookup behaves like lookup, a deprecated
+// variable already in reports isn't reported again
+func (e EnvTree) reportingLookup(keyChain []string, reports deprecationReports) func() (string, bool) {
+	fun := getNodeValueByKeyChain(e.root, &keyChain)
+
+	return func() (string, bool) {
+		v, ok := fun()
+
+		if ok && e.HasSubTreeValueUnsecured(keyChain...) {
+			reports.reportVariable(e.ref(keyChain).name)
+		}
+
+		return v, ok
+	}
+}
+
+// ref returns variable reference of a key chain, full variable name
+// is rebuilt from the path leading to current tree
+func (e EnvTree) ref(keyChain []string) varRef {
+	if len(e.path) == 0 {
+		return varRef{keyChain, strings.Join(keyChain, e.delimiter)}
+	}
+
+	return varRef{keyChain, strings.Join(append(append([]string{}, e.path...), keyChain...), e.delimiter)}
 }
 
 func getRootValue(tree EnvTree) func() (string, bool) {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"time"
 )
 
 func ExampleEnvTree_FindString() {
@@ -28,7 +29,7 @@ func ExampleEnvTree_FindString() {
 	fmt.Println(env.FindString("ENVH", "DB", "WHATEVER"))
 	// Output:
 	// foo <nil>
-	//  Variable not found
+	//  Variable "ENVH_DB_WHATEVER" not found
 }
 
 func ExampleEnvTree_FindStringUnsecured() {
@@ -77,8 +78,8 @@ func ExampleEnvTree_FindInt() {
 	fmt.Println(env.FindInt("ENVH", "DB", "WHATEVER"))
 	// Output:
 	// 3306 <nil>
-	// 0 Value "foo" can't be converted to type "int"
-	// 0 Variable not found
+	// 0 Value "foo" of variable "ENVH_DB_USERNAME" can't be converted to type "int"
+	// 0 Variable "ENVH_DB_WHATEVER" not found
 }
 
 func ExampleEnvTree_FindIntUnsecured() {
@@ -129,8 +130,8 @@ func ExampleEnvTree_FindBool() {
 	fmt.Println(env.FindBool("ENVH", "DB", "WHATEVER"))
 	// Output:
 	// true <nil>
-	// false Value "foo" can't be converted to type "bool"
-	// false Variable not found
+	// false Value "foo" of variable "ENVH_DB_USERNAME" can't be converted to type "bool"
+	// false Variable "ENVH_DB_WHATEVER" not found
 }
 
 func ExampleEnvTree_FindBoolUnsecured() {
@@ -181,8 +182,8 @@ func ExampleEnvTree_FindFloat() {
 	fmt.Println(env.FindFloat("ENVH", "DB", "WHATEVER"))
 	// Output:
 	// 95.6 <nil>
-	// 0 Value "foo" can't be converted to type "float"
-	// 0 Variable not found
+	// 0 Value "foo" of variable "ENVH_DB_USERNAME" can't be converted to type "float"
+	// 0 Variable "ENVH_DB_WHATEVER" not found
 }
 
 func ExampleEnvTree_FindFloatUnsecured() {
@@ -354,7 +355,7 @@ func ExampleEnvTree_FindSubTree() {
 	// Output:
 	// [PASSWORD PORT USAGE USERNAME] <nil>
 	// [ENABLED HOST PASSWORD USERNAME] <nil>
-	// {<nil>} No node found at path "ENVH -> MAILER -> WHATEVER"
+	// {<nil>  []} No node found at path "ENVH -> MAILER -> WHATEVER"
 }
 
 func ExampleEnvTree_FindSubTreeUnsecured() {
@@ -390,7 +391,7 @@ func ExampleEnvTree_FindSubTreeUnsecured() {
 	// Output:
 	// [PASSWORD PORT USAGE USERNAME]
 	// [ENABLED HOST PASSWORD USERNAME]
-	// {<nil>}
+	// {<nil>  []}
 }
 
 func ExampleEnvTree_GetKey() {
@@ -838,5 +839,105 @@ func ExampleEnvTree_PopulateStructWithStrictMode() {
 
 	fmt.Println(err)
 	// Output:
-	// Variable not found
+	// 1 error(s) occurred while populating struct :
+	//   - Field "MAILER.ENABLED" : Variable "ENVH_MAILER_ENABLED" not found
+}
+
+func ExampleEnvTree_PopulateStructWithStrictKeys() {
+	type ENVH struct {
+		DB struct {
+			USERNAME string
+			PASSWORD string
+			PORT     int
+		}
+	}
+
+	os.Clearenv()
+	setEnv("ENVH_DB_USERNAME", "foo")
+	setEnv("ENVH_DB_PASWORD", "bar")
+	setEnv("ENVH_DB_PORT", "3306")
+
+	env, err := NewEnvTree("^ENVH", "_")
+
+	if err != nil {
+		return
+	}
+
+	s := ENVH{}
+
+	err = env.PopulateStructWithStrictKeys(&s)
+
+	fmt.Println(err)
+	// Output:
+	// 1 error(s) occurred while populating struct :
+	//   - Variable "ENVH_DB_PASWORD" doesn't match any field, did you mean "ENVH_DB_PASSWORD" ?
+}
+
+func ExampleEnvTree_PopulateStructAt() {
+	type DB struct {
+		HOST string
+		PORT int
+	}
+
+	os.Clearenv()
+	setEnv("APP_PRIMARY_DB_HOST", "primary")
+	setEnv("APP_PRIMARY_DB_PORT", "3306")
+	setEnv("APP_REPLICA_DB_HOST", "replica")
+	setEnv("APP_REPLICA_DB_PORT", "3307")
+
+	env, err := NewEnvTree("^APP", "_")
+
+	if err != nil {
+		return
+	}
+
+	primary := DB{}
+	replica := DB{}
+
+	if err = env.PopulateStructAt(&primary, "APP", "PRIMARY", "DB"); err != nil {
+		return
+	}
+
+	if err = env.PopulateStructAt(&replica, "APP", "REPLICA", "DB"); err != nil {
+		return
+	}
+
+	fmt.Printf("%+v %+v\n", primary, replica)
+	// Output:
+	// {HOST:primary PORT:3306} {HOST:replica PORT:3307}
+}
+
+func ExampleEnvTree_Populate() {
+	type Server struct {
+		Host    string
+		Port    int
+		Timeout time.Duration
+	}
+
+	os.Clearenv()
+	setEnv("APP_SERVER_HOST", "localhost")
+	setEnv("APP_SERVER_PORT", "8080")
+	setEnv("APP_SERVER_TIMEOUT", "30s")
+
+	env, err := NewEnvTree("^APP", "_")
+
+	if err != nil {
+		return
+	}
+
+	server := Server{}
+
+	err = env.Populate(&server,
+		WithRootKey("APP", "SERVER"),
+		WithNamingStrategy(UpperCaseNaming),
+		WithDecoder(time.ParseDuration),
+	)
+
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("%+v\n", server)
+	// Output:
+	// {Host:localhost Port:8080 Timeout:30s}
 }
@@ -1,8 +1,11 @@
 package envh
 
 import (
+	"errors"
+	"fmt"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -91,7 +94,7 @@ func TestFindStringFromTree(t *testing.T) {
 
 	value, err = envTree.FindString("ENVH_TEST1000")
 
-	assert.EqualError(t, err, "Variable not found", "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Variable "ENVH_TEST1000" not found`, "Must return an error when variable can't be found")
 	assert.Equal(t, "", value, "Must return empty string")
 }
 
@@ -126,12 +129,12 @@ func TestFindIntFromTree(t *testing.T) {
 
 	value, err = envTree.FindInt("TEST100")
 
-	assert.EqualError(t, err, "Variable not found", "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Variable "TEST100" not found`, "Must return an error when variable can't be found")
 	assert.Equal(t, 0, value, "Must return value")
 
 	value, err = envTree.FindInt("ENVH", "TEST1", "TEST2", "STRING")
 
-	assert.EqualError(t, err, `Value "test" can't be converted to type "int"`, "Must return an error when variable can't be converted")
+	assert.EqualError(t, err, `Value "test" of variable "ENVH_TEST1_TEST2_STRING" can't be converted to type "int"`, "Must return an error when variable can't be converted")
 	assert.Equal(t, 0, value, "Must return empty string")
 }
 
@@ -171,12 +174,12 @@ func TestFindBoolFromTree(t *testing.T) {
 
 	value, err = envTree.FindBool("TEST100")
 
-	assert.EqualError(t, err, "Variable not found", "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Variable "TEST100" not found`, "Must return an error when variable can't be found")
 	assert.Equal(t, false, value, "Must return value")
 
 	value, err = envTree.FindBool("ENVH", "TEST1", "TEST2", "STRING")
 
-	assert.EqualError(t, err, `Value "test" can't be converted to type "bool"`, "Must return an error when variable can't be converted")
+	assert.EqualError(t, err, `Value "test" of variable "ENVH_TEST1_TEST2_STRING" can't be converted to type "bool"`, "Must return an error when variable can't be converted")
 	assert.Equal(t, false, value, "Must return empty string")
 }
 
@@ -216,12 +219,12 @@ func TestFindFloatFromTree(t *testing.T) {
 
 	value, err = envTree.FindFloat("TEST100")
 
-	assert.EqualError(t, err, "Variable not found", "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Variable "TEST100" not found`, "Must return an error when variable can't be found")
 	assert.Equal(t, float32(0), value, "Must return value")
 
 	value, err = envTree.FindFloat("ENVH", "TEST1", "TEST2", "STRING")
 
-	assert.EqualError(t, err, `Value "test" can't be converted to type "float"`, "Must return an error when variable can't be converted")
+	assert.EqualError(t, err, `Value "test" of variable "ENVH_TEST1_TEST2_STRING" can't be converted to type "float"`, "Must return an error when variable can't be converted")
 	assert.Equal(t, float32(0), value, "Must return empty string")
 }
 
@@ -370,6 +373,45 @@ func TestFindSubTreeFromTree(t *testing.T) {
 	assert.EqualError(t, err, `No node found at path "ENVH -> TEST11 -> TEST12 -> TEST13 -> TEST10000"`, "Must returns an error, node doesn't exists")
 }
 
+func TestErrorsFromSubTreeCarryVariableContext(t *testing.T) {
+	setEnv("ENVH_TEST11_TEST12_TEST13_TEST14", "test1")
+
+	envTree, err := NewEnvTree("ENVH", "_")
+
+	assert.NoError(t, err, "Must returns no error")
+
+	tree, err := envTree.FindSubTree("ENVH", "TEST11", "TEST12")
+
+	assert.NoError(t, err, "Must returns no error")
+
+	_, err = tree.FindInt("TEST13", "TEST14")
+
+	wrongTypeErr := WrongTypeError{}
+
+	assert.True(t, errors.As(err, &wrongTypeErr), "Must return a WrongTypeError")
+	assert.True(t, errors.Is(err, ErrWrongType), "Must match sentinel error")
+	assert.True(t, errors.Is(err, strconv.ErrSyntax), "Must wrap strconv error")
+	assert.Equal(t, []string{"TEST13", "TEST14"}, wrongTypeErr.KeyChain)
+	assert.Equal(t, "ENVH_TEST11_TEST12_TEST13_TEST14", wrongTypeErr.Variable)
+	assert.Equal(t, "int", wrongTypeErr.Type)
+
+	_, err = tree.FindString("TEST13", "TEST99")
+
+	assert.True(t, errors.Is(err, ErrVariableNotFound), "Must match sentinel error")
+	assert.Equal(t, VariableNotFoundError{[]string{"TEST13", "TEST99"}, "ENVH_TEST11_TEST12_TEST13_TEST99"}, err)
+	assert.EqualError(t, err, `Variable "ENVH_TEST11_TEST12_TEST13_TEST99" not found`)
+
+	_, err = tree.FindSubTree("TEST13", "TEST99")
+
+	nodeNotFoundErr := NodeNotFoundError{}
+
+	assert.True(t, errors.As(err, &nodeNotFoundErr), "Must return a NodeNotFoundError")
+	assert.True(t, errors.Is(err, ErrNodeNotFound), "Must match sentinel error")
+	assert.Equal(t, "ENVH_TEST11_TEST12_TEST13_TEST99", nodeNotFoundErr.Variable)
+
+	restoreEnvs()
+}
+
 func TestFindSubTreeUnsecuredFromTree(t *testing.T) {
 	setEnv("ENVH_TEST11_TEST12_TEST13_TEST14", "test1")
 	setEnv("ENVH_TEST11_TEST12_TEST13_TEST15", "test2")
@@ -413,7 +455,7 @@ func TestGetStringFromTree(t *testing.T) {
 
 	value, err = subTree.GetString()
 
-	assert.EqualError(t, err, "Variable not found", "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Variable "ENVH_TEST1_TEST2" not found`, "Must return an error when variable can't be found")
 	assert.Equal(t, "", value, "Must return empty string")
 }
 
@@ -464,7 +506,7 @@ func TestGetIntFromTree(t *testing.T) {
 
 	value, err = subTree.GetInt()
 
-	assert.EqualError(t, err, "Variable not found", "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Variable "ENVH_TEST1_TEST2" not found`, "Must return an error when variable can't be found")
 	assert.Equal(t, 0, value, "Must return value")
 
 	subTree, err = envTree.FindSubTree("ENVH", "TEST1", "TEST2", "STRING")
@@ -473,7 +515,7 @@ func TestGetIntFromTree(t *testing.T) {
 
 	value, err = subTree.GetInt()
 
-	assert.EqualError(t, err, `Value "test" can't be converted to type "int"`, "Must return an error when variable can't be converted")
+	assert.EqualError(t, err, `Value "test" of variable "ENVH_TEST1_TEST2_STRING" can't be converted to type "int"`, "Must return an error when variable can't be converted")
 	assert.Equal(t, 0, value, "Must return empty string")
 }
 
@@ -533,7 +575,7 @@ func TestGetBoolFromTree(t *testing.T) {
 
 	value, err = subTree.GetBool()
 
-	assert.EqualError(t, err, "Variable not found", "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Variable "ENVH_TEST1_TEST2" not found`, "Must return an error when variable can't be found")
 	assert.Equal(t, false, value, "Must return value")
 
 	subTree, err = envTree.FindSubTree("ENVH", "TEST1", "TEST2", "STRING")
@@ -542,7 +584,7 @@ func TestGetBoolFromTree(t *testing.T) {
 
 	value, err = subTree.GetBool()
 
-	assert.EqualError(t, err, `Value "test" can't be converted to type "bool"`, "Must return an error when variable can't be converted")
+	assert.EqualError(t, err, `Value "test" of variable "ENVH_TEST1_TEST2_STRING" can't be converted to type "bool"`, "Must return an error when variable can't be converted")
 	assert.Equal(t, false, value, "Must return empty string")
 }
 
@@ -602,7 +644,7 @@ func TestGetFloatFromTree(t *testing.T) {
 
 	value, err = subTree.GetFloat()
 
-	assert.EqualError(t, err, "Variable not found", "Must return an error when variable can't be found")
+	assert.EqualError(t, err, `Variable "ENVH_TEST1_TEST2" not found`, "Must return an error when variable can't be found")
 	assert.Equal(t, float32(0), value, "Must return value")
 
 	subTree, err = envTree.FindSubTree("ENVH", "TEST1", "TEST2", "STRING")
@@ -611,7 +653,7 @@ func TestGetFloatFromTree(t *testing.T) {
 
 	value, err = subTree.GetFloat()
 
-	assert.EqualError(t, err, `Value "test" can't be converted to type "float"`, "Must return an error when variable can't be converted")
+	assert.EqualError(t, err, `Value "test" of variable "ENVH_TEST1_TEST2_STRING" can't be converted to type "float"`, "Must return an error when variable can't be converted")
 	assert.Equal(t, float32(0), value, "Must return empty string")
 }
 
@@ -750,7 +792,77 @@ func TestPopulateStructWithStrictModeWithAnError(t *testing.T) {
 
 	err = envTree.PopulateStructWithStrictMode(&actual)
 
-	assert.EqualError(t, err, "Variable not found")
+	assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "WHATEVER" : Variable "TEST_WHATEVER" not found`)
+
+	restoreEnvs()
+}
+
+type DBCONFIG struct {
+	HOST string `validate:"hostname"`
+	PORT int
+	URL  string
+}
+
+func (d *DBCONFIG) AfterPopulate(tree *EnvTree) error {
+	d.URL = fmt.Sprintf("%s:%d", d.HOST, d.PORT)
+
+	return nil
+}
+
+func TestPopulateStructAt(t *testing.T) {
+	setEnv("APP_PRIMARY_DB_HOST", "primary")
+	setEnv("APP_PRIMARY_DB_PORT", "3306")
+	setEnv("APP_REPLICA_DB_HOST", "replica")
+	setEnv("APP_REPLICA_DB_PORT", "3307")
+
+	envTree, err := NewEnvTree("^APP", "_")
+
+	assert.NoError(t, err)
+
+	primary := DBCONFIG{}
+	replica := DBCONFIG{}
+
+	assert.NoError(t, envTree.PopulateStructAt(&primary, "APP", "PRIMARY", "DB"))
+	assert.Equal(t, DBCONFIG{"primary", 3306, "primary:3306"}, primary)
+
+	subTree, err := envTree.FindSubTree("APP", "REPLICA", "DB")
+
+	assert.NoError(t, err)
+	assert.NoError(t, subTree.PopulateStructAt(&replica), "Must use tree root as struct")
+	assert.Equal(t, DBCONFIG{"replica", 3307, "replica:3307"}, replica)
+
+	anonymous := struct {
+		HOST string
+	}{}
+
+	assert.NoError(t, envTree.PopulateStructAt(&anonymous, "APP", "PRIMARY", "DB"))
+	assert.Equal(t, "primary", anonymous.HOST)
+
+	restoreEnvs()
+}
+
+func TestPopulateStructAtWithErrors(t *testing.T) {
+	setEnv("APP_PRIMARY_DB_HOST", "-primary")
+	setEnv("APP_PRIMARY_DB_PORT", "port")
+
+	envTree, err := NewEnvTree("^APP", "_")
+
+	assert.NoError(t, err)
+
+	subTree, err := envTree.FindSubTree("APP", "PRIMARY", "DB")
+
+	assert.NoError(t, err)
+
+	err = subTree.PopulateStructAt(&DBCONFIG{})
+
+	assert.EqualError(t, err, `2 error(s) occurred while populating struct :
+  - Field "HOST" : Value "-primary" of variable "APP_PRIMARY_DB_HOST" is invalid : must be a valid hostname
+  - Field "PORT" : Value "port" of variable "APP_PRIMARY_DB_PORT" can't be converted to type "int"`)
+
+	err = envTree.PopulateStructAt(DBCONFIG{}, "APP")
+
+	assert.EqualError(t, err, `Type "struct" is not supported : you must provide "pointer to struct"`)
 
 	restoreEnvs()
 }
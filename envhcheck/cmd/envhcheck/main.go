@@ -0,0 +1,14 @@
+// Command envhcheck reports config structs envh can't populate,
+// checkout envhcheck package documentation for reported issues.
+//
+//	go run github.com/antham/envh/envhcheck/cmd/envhcheck ./...
+package main
+
+import (
+	"github.com/antham/envh/envhcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(envhcheck.Analyzer)
+}
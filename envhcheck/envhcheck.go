@@ -0,0 +1,733 @@
+// Package envhcheck defines an analyzer reporting, when code is checked instead of
+// when config is loaded, config structs given to envh populate functions which can't be
+// populated : fields of unsupported types, malformed envh and validate tags, several fields
+// mapped to a same key, default values which can't be converted to field type and
+// keys compared in a StructWalker which don't match any field.
+//
+// Decoders are only known when they're registered, with RegisterDecoder or WithDecoder,
+// in the package being analyzed, and interface fields are accepted when variants are given.
+package envhcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/antham/envh/envhcheck/internal/tagspec"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const envhPath = "github.com/antham/envh"
+
+const unsupportedTypeMessage = "int32, float32, string, boolean or struct"
+
+// mapEntryKey matches any key of a map of structs in a key chain
+const mapEntryKey = "*"
+
+// Analyzer reports config structs envh can't populate
+var Analyzer = &analysis.Analyzer{
+	Name:     "envhcheck",
+	Doc:      "report config structs given to envh populate functions which can't be populated",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// populateFunctions gives, for every receiver type, methods populating
+// a struct and whether variables are flat
+var populateFunctions = map[string]map[string]bool{
+	"EnvTree": {
+		"PopulateStruct":               false,
+		"PopulateStructWithStrictMode": false,
+		"PopulateStructWithStrictKeys": false,
+		"PopulateStructAt":             false,
+		"Populate":                     false,
+	},
+	"Env": {
+		"Populate": true,
+	},
+}
+
+// callOptions holds settings of a populate call,
+// extracted from options given
+type callOptions struct {
+	flat        bool
+	tagName     string
+	naming      func(string) string
+	rootKeys    []string
+	hasRootKeys bool
+	variants    bool
+	decoders    []types.Type
+	// unknown is true when options can't be statically evaluated,
+	// types and walker keys aren't checked in that case
+	unknown bool
+}
+
+type checker struct {
+	pass     *analysis.Pass
+	decoders []types.Type
+	reported map[string]bool
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	c := &checker{pass, findDecoders(pass), map[string]bool{}}
+	inspect := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	inspect.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn, recv := calledMethod(pass, call)
+		flat, ok := populateFunctions[recv][fn]
+
+		if !ok || len(call.Args) == 0 {
+			return
+		}
+
+		opts := c.callOptions(call, fn, flat)
+		c.checkCall(call, fn, opts)
+	})
+
+	return nil, nil
+}
+
+// calledMethod returns name and receiver type name of an envh method called
+func calledMethod(pass *analysis.Pass, call *ast.CallExpr) (string, string) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+
+	if !ok {
+		return "", ""
+	}
+
+	fn, ok := pass.TypesInfo.Uses[sel.Sel].(*types.Func)
+
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != envhPath {
+		return "", ""
+	}
+
+	recv := fn.Type().(*types.Signature).Recv()
+
+	if recv == nil {
+		return "", ""
+	}
+
+	typ := recv.Type()
+
+	if ptr, ok := typ.(*types.Pointer); ok {
+		typ = ptr.Elem()
+	}
+
+	named, ok := typ.(*types.Named)
+
+	if !ok {
+		return "", ""
+	}
+
+	return fn.Name(), named.Obj().Name()
+}
+
+// envhFunction returns name of an envh function called
+func envhFunction(pass *analysis.Pass, expr ast.Expr) (string, *ast.CallExpr) {
+	call, ok := expr.(*ast.CallExpr)
+
+	if !ok {
+		return "", nil
+	}
+
+	fun := call.Fun
+
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+
+	var ident *ast.Ident
+
+	switch f := fun.(type) {
+	case *ast.Ident:
+		ident = f
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	default:
+		return "", nil
+	}
+
+	fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != envhPath {
+		return "", nil
+	}
+
+	return fn.Name(), call
+}
+
+// findDecoders returns types a decoder is registered globally for in the package
+func findDecoders(pass *analysis.Pass) []types.Type {
+	decoders := []types.Type{}
+
+	for ident, instance := range pass.TypesInfo.Instances {
+		fn, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+
+		if !ok || fn.Pkg() == nil || fn.Pkg().Path() != envhPath || instance.TypeArgs.Len() == 0 {
+			continue
+		}
+
+		if fn.Name() == "RegisterDecoder" {
+			decoders = append(decoders, instance.TypeArgs.At(0))
+		}
+	}
+
+	return decoders
+}
+
+func (c *checker) callOptions(call *ast.CallExpr, fn string, flat bool) callOptions {
+	opts := callOptions{flat: flat, tagName: "envh", naming: func(s string) string { return s }}
+
+	if flat {
+		opts.tagName = "env"
+		opts.hasRootKeys = true
+		opts.rootKeys = []string{}
+	}
+
+	if fn == "PopulateStructAt" {
+		opts.rootKeys, opts.hasRootKeys = c.constantStrings(call.Args[1:])
+		opts.unknown = !opts.hasRootKeys || call.Ellipsis.IsValid()
+
+		return opts
+	}
+
+	if fn != "Populate" {
+		return opts
+	}
+
+	if call.Ellipsis.IsValid() {
+		opts.unknown = true
+
+		return opts
+	}
+
+	for _, arg := range call.Args[1:] {
+		name, option := envhFunction(c.pass, arg)
+
+		switch name {
+		case "WithStrictMode", "WithUnknownKeyPolicy", "WithHook", "WithProvenance":
+		case "WithDecoder":
+			if sig, ok := c.pass.TypesInfo.TypeOf(option.Args[0]).(*types.Signature); ok && sig.Results().Len() > 0 {
+				opts.decoders = append(opts.decoders, sig.Results().At(0).Type())
+			}
+		case "WithTagName":
+			if tags, ok := c.constantStrings(option.Args); ok && len(tags) == 1 {
+				opts.tagName = tags[0]
+			} else {
+				opts.unknown = true
+			}
+		case "WithRootKey":
+			if opts.rootKeys, opts.hasRootKeys = c.constantStrings(option.Args); !opts.hasRootKeys || option.Ellipsis.IsValid() {
+				opts.unknown = true
+			}
+		case "WithNamingStrategy":
+			switch naming, _ := c.envhIdent(option.Args[0]); naming {
+			case "IdentityNaming":
+			case "UpperCaseNaming":
+				opts.naming = strings.ToUpper
+			default:
+				opts.unknown = true
+			}
+		case "WithVariants":
+			opts.variants = true
+		default:
+			// walkers can handle any field, as other options can't be evaluated
+			opts.unknown = true
+		}
+	}
+
+	return opts
+}
+
+func (c *checker) constantStrings(exprs []ast.Expr) ([]string, bool) {
+	values := []string{}
+
+	for _, expr := range exprs {
+		tv, ok := c.pass.TypesInfo.Types[expr]
+
+		if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+			return nil, false
+		}
+
+		values = append(values, constant.StringVal(tv.Value))
+	}
+
+	return values, true
+}
+
+func (c *checker) envhIdent(expr ast.Expr) (string, bool) {
+	var ident *ast.Ident
+
+	switch e := expr.(type) {
+	case *ast.Ident:
+		ident = e
+	case *ast.SelectorExpr:
+		ident = e.Sel
+	default:
+		return "", false
+	}
+
+	obj := c.pass.TypesInfo.Uses[ident]
+
+	if obj == nil || obj.Pkg() == nil || obj.Pkg().Path() != envhPath {
+		return "", false
+	}
+
+	return obj.Name(), true
+}
+
+func (c *checker) checkCall(call *ast.CallExpr, fn string, opts callOptions) {
+	arg := call.Args[0]
+	typ := c.pass.TypesInfo.TypeOf(arg)
+
+	if typ == nil {
+		return
+	}
+
+	ptr, ok := typ.Underlying().(*types.Pointer)
+
+	if _, isInterface := typ.Underlying().(*types.Interface); isInterface {
+		return
+	}
+
+	if !ok {
+		c.report(arg.Pos(), fmt.Sprintf(`%s expects a pointer to struct, got "%s"`, fn, types.TypeString(typ, types.RelativeTo(c.pass.Pkg))))
+
+		return
+	}
+
+	st, ok := ptr.Elem().Underlying().(*types.Struct)
+
+	if !ok {
+		c.report(arg.Pos(), fmt.Sprintf(`%s expects a pointer to struct, got "%s"`, fn, types.TypeString(typ, types.RelativeTo(c.pass.Pkg))))
+
+		return
+	}
+
+	name := types.TypeString(ptr.Elem(), func(*types.Package) string { return "" })
+	rootKeys := []string{name}
+
+	if opts.hasRootKeys {
+		rootKeys = opts.rootKeys
+	}
+
+	s := &structCheck{checker: c, opts: opts, pos: arg.Pos(), root: name, chains: [][]string{}}
+	walker, hasWalker := c.walkerKeys(ptr.Elem(), rootKeys, opts)
+	s.walker = walker
+
+	if hasWalker && opts.flat {
+		s.opts.unknown = true
+	}
+
+	s.checkStruct(st, rootKeys, []string{}, map[string]string{}, map[*types.Struct]bool{})
+
+	if s.opts.unknown {
+		return
+	}
+
+	for _, key := range walker {
+		if !s.isKnownChain(key.chain) {
+			c.report(key.pos, fmt.Sprintf(`key "%s" doesn't match any field of "%s"`, key.value, name))
+		}
+	}
+}
+
+// walkerKey is a string literal found in a Walk method
+// looking like a full variable name
+type walkerKey struct {
+	value string
+	chain []string
+	pos   token.Pos
+}
+
+// walkerKeys returns keys compared in Walk method of a struct, a literal is considered
+// as a key when it starts with struct root key followed by a delimiter
+func (c *checker) walkerKeys(typ types.Type, rootKeys []string, opts callOptions) ([]walkerKey, bool) {
+	sel := types.NewMethodSet(types.NewPointer(typ)).Lookup(c.pass.Pkg, "Walk")
+
+	if sel == nil {
+		return []walkerKey{}, false
+	}
+
+	method, ok := sel.Obj().(*types.Func)
+	keys := []walkerKey{}
+
+	if !ok || len(rootKeys) == 0 {
+		return keys, true
+	}
+
+	for _, f := range c.pass.Files {
+		for _, decl := range f.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+
+			if !ok || fd.Body == nil || c.pass.TypesInfo.Defs[fd.Name] != method {
+				continue
+			}
+
+			ast.Inspect(fd.Body, func(n ast.Node) bool {
+				lit, ok := n.(*ast.BasicLit)
+
+				if !ok || lit.Kind != token.STRING {
+					return true
+				}
+
+				value, err := strconv.Unquote(lit.Value)
+
+				if err != nil || !strings.HasPrefix(value, rootKeys[0]) || len(value) == len(rootKeys[0]) {
+					return true
+				}
+
+				delimiter, _ := firstRune(value[len(rootKeys[0]):])
+
+				if unicode.IsLetter(delimiter) || unicode.IsDigit(delimiter) {
+					return true
+				}
+
+				keys = append(keys, walkerKey{value, strings.Split(value, string(delimiter)), lit.Pos()})
+
+				return true
+			})
+		}
+	}
+
+	return keys, true
+}
+
+func firstRune(s string) (rune, bool) {
+	for _, r := range s {
+		return r, true
+	}
+
+	return 0, false
+}
+
+// structCheck checks a struct given to a populate function
+type structCheck struct {
+	*checker
+	opts   callOptions
+	pos    token.Pos
+	root   string
+	walker []walkerKey
+	chains [][]string
+}
+
+func (s *structCheck) checkStruct(st *types.Struct, chain []string, path []string, scope map[string]string, visiting map[*types.Struct]bool) {
+	if visiting[st] {
+		return
+	}
+
+	visiting[st] = true
+	defer delete(visiting, st)
+
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+
+		if !field.Exported() {
+			continue
+		}
+
+		fieldPath := append(append([]string{}, path...), field.Name())
+		tag := parseTag(field, st.Tag(i), s.opts)
+		pos := s.fieldPos(field)
+		at := func(format string, args ...interface{}) {
+			s.report(pos, fmt.Sprintf(`field "%s" : %s`, strings.Join(append([]string{s.root}, fieldPath...), "."), fmt.Sprintf(format, args...)))
+		}
+
+		if tag.err != "" {
+			at("%s", tag.err)
+		}
+
+		for _, message := range checkValidationTag(st, st.Tag(i)) {
+			at("%s", message)
+		}
+
+		if !s.opts.unknown {
+			for _, key := range tag.keys {
+				if other, ok := scope[key]; ok {
+					at(`key "%s" is already mapped to field "%s"`, key, other)
+				} else {
+					scope[key] = strings.Join(append([]string{s.root}, fieldPath...), ".")
+				}
+			}
+		}
+
+		keyChains := [][]string{}
+
+		for _, key := range tag.keys {
+			keyChains = append(keyChains, append(append([]string{}, chain...), key))
+		}
+
+		s.chains = append(s.chains, keyChains...)
+
+		if s.isWalked(keyChains) {
+			continue
+		}
+
+		s.checkFieldType(field.Type(), tag, keyChains[0], fieldPath, scope, visiting, at)
+	}
+}
+
+func (s *structCheck) checkFieldType(typ types.Type, tag fieldTag, keyChain []string, path []string, scope map[string]string, visiting map[*types.Struct]bool, at func(format string, args ...interface{})) {
+	if s.hasDecoder(typ) {
+		return
+	}
+
+	if inner, ok := secretType(typ); ok {
+		typ = inner
+
+		if s.hasDecoder(typ) {
+			return
+		}
+	}
+
+	structChain := keyChain
+	structScope := map[string]string{}
+
+	if s.opts.flat {
+		structChain = keyChain[:len(keyChain)-1]
+		structScope = scope
+	}
+
+	switch u := typ.Underlying().(type) {
+	case *types.Basic:
+		if !isSupportedBasic(u) {
+			s.unsupported(typ, at)
+
+			return
+		}
+
+		if tag.hasDefault {
+			if err := checkDefault(tag.defaultValue, u); err != "" {
+				at(`default value "%s" can't be converted to type "%s"`, tag.defaultValue, err)
+			}
+		}
+	case *types.Struct:
+		s.checkStruct(u, structChain, path, structScope, visiting)
+	case *types.Map:
+		key, isBasic := u.Key().Underlying().(*types.Basic)
+		elem := u.Elem()
+
+		if ptr, ok := elem.Underlying().(*types.Pointer); ok {
+			elem = ptr.Elem()
+		}
+
+		st, isStruct := elem.Underlying().(*types.Struct)
+
+		if s.opts.flat || !isBasic || key.Kind() != types.String || !isStruct {
+			s.unsupported(typ, at)
+
+			return
+		}
+
+		s.checkStruct(st, append(append([]string{}, keyChain...), mapEntryKey), path, map[string]string{}, visiting)
+	case *types.Interface:
+		if !s.opts.variants {
+			s.unsupported(typ, at)
+		}
+	default:
+		s.unsupported(typ, at)
+	}
+}
+
+func (s *structCheck) unsupported(typ types.Type, at func(format string, args ...interface{})) {
+	if s.opts.unknown {
+		return
+	}
+
+	at(`type "%s" is not supported : you must provide "%s"`, types.TypeString(typ, types.RelativeTo(s.pass.Pkg)), unsupportedTypeMessage)
+}
+
+func (s *structCheck) hasDecoder(typ types.Type) bool {
+	for _, d := range append(append([]types.Type{}, s.decoders...), s.opts.decoders...) {
+		if types.Identical(d, typ) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isWalked returns true if a StructWalker handles one of key chains
+func (s *structCheck) isWalked(keyChains [][]string) bool {
+	for _, keyChain := range keyChains {
+		for _, key := range s.walker {
+			if matchChain(key.chain, keyChain) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isKnownChain returns true if key chain matches a field, or leads to one
+func (s *structCheck) isKnownChain(chain []string) bool {
+	for _, c := range s.chains {
+		if len(chain) <= len(c) && matchChain(chain, c[:len(chain)]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// matchChain compares key chains, a map entry key matches any key
+func matchChain(chain []string, pattern []string) bool {
+	if len(chain) != len(pattern) {
+		return false
+	}
+
+	for i := range chain {
+		if chain[i] != pattern[i] && pattern[i] != mapEntryKey {
+			return false
+		}
+	}
+
+	return true
+}
+
+// fieldPos returns position of a field when it's declared
+// in analyzed package, position of populated struct otherwise
+func (s *structCheck) fieldPos(field *types.Var) token.Pos {
+	for _, f := range s.pass.Files {
+		if f.Pos() <= field.Pos() && field.Pos() <= f.End() {
+			return field.Pos()
+		}
+	}
+
+	return s.pos
+}
+
+func (c *checker) report(pos token.Pos, message string) {
+	key := fmt.Sprintf("%d %s", pos, message)
+
+	if c.reported[key] {
+		return
+	}
+
+	c.reported[key] = true
+	c.pass.Reportf(pos, "%s", message)
+}
+
+// fieldTag holds keys and settings of a field
+type fieldTag struct {
+	keys         []string
+	defaultValue string
+	hasDefault   bool
+	err          string
+}
+
+func parseTag(field *types.Var, structTag string, opts callOptions) fieldTag {
+	tags := reflect.StructTag(structTag)
+	tag := fieldTag{keys: []string{opts.naming(field.Name())}}
+	tag.defaultValue, tag.hasDefault = tags.Lookup("default")
+	value, ok := tags.Lookup(opts.tagName)
+
+	if !ok {
+		return tag
+	}
+
+	options := strings.Split(value, ",")
+
+	if keys := tagspec.ParseKeys(options[0]); len(keys) > 0 {
+		tag.keys = keys
+	}
+
+	for _, option := range options[1:] {
+		if strings.TrimSpace(option) != tagspec.SecretOption {
+			tag.err = fmt.Sprintf(`tag %s:"%s" is invalid : option "%s" doesn't exist`, opts.tagName, value, option)
+			tag.keys = []string{opts.naming(field.Name())}
+
+			return tag
+		}
+	}
+
+	return tag
+}
+
+// checkValidationTag returns every issue of a validate tag
+func checkValidationTag(st *types.Struct, structTag string) []string {
+	value, ok := reflect.StructTag(structTag).Lookup("validate")
+
+	if !ok || value == "" {
+		return []string{}
+	}
+
+	invalid := func(reason string, args ...interface{}) []string {
+		return []string{fmt.Sprintf(`tag validate:"%s" is invalid : %s`, value, fmt.Sprintf(reason, args...))}
+	}
+
+	rules, err := tagspec.ParseRules(value)
+
+	if err != nil {
+		return invalid("%s", err)
+	}
+
+	for _, r := range rules {
+		switch {
+		case r.Name == "regex":
+			if _, err := regexp.Compile(r.Param); err != nil {
+				return invalid("%s", err)
+			}
+		case tagspec.Rules[r.Name].CrossField:
+			sibling := strings.SplitN(r.Param, " ", 2)[0]
+
+			if !hasField(st, sibling) {
+				return invalid(`field "%s" doesn't exist`, sibling)
+			}
+		}
+	}
+
+	return []string{}
+}
+
+func hasField(st *types.Struct, name string) bool {
+	for i := 0; i < st.NumFields(); i++ {
+		if st.Field(i).Name() == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isSupportedBasic(b *types.Basic) bool {
+	switch b.Kind() {
+	case types.Int, types.Float32, types.String, types.Bool:
+		return true
+	}
+
+	return false
+}
+
+// checkDefault returns name of the type a default value can't be converted to,
+// an empty string is returned if it can be converted
+func checkDefault(value string, b *types.Basic) string {
+	kinds := map[types.BasicKind]reflect.Kind{types.Int: reflect.Int, types.Float32: reflect.Float32, types.Bool: reflect.Bool}
+
+	return tagspec.CheckValue(value, kinds[b.Kind()])
+}
+
+// secretType returns type wrapped by an envh.Secret
+func secretType(typ types.Type) (types.Type, bool) {
+	named, ok := typ.(*types.Named)
+
+	if !ok || named.Obj().Pkg() == nil || named.Obj().Pkg().Path() != envhPath || named.Obj().Name() != "Secret" || named.TypeArgs().Len() != 1 {
+		return nil, false
+	}
+
+	return named.TypeArgs().At(0), true
+}
@@ -0,0 +1,85 @@
+package a
+
+import (
+	"strings"
+	"time"
+
+	"github.com/antham/envh"
+)
+
+type Storage interface {
+	Name() string
+}
+
+type DB struct {
+	HOST     string `validate:"hostname"`
+	PORT     int    `default:"http"` // want `field "CONFIG.DB.PORT" : default value "http" can't be converted to type "int"`
+	PASSWORD envh.Secret[string]
+	TIMEOUT  time.Duration // want `field "CONFIG.DB.TIMEOUT" : type "time.Duration" is not supported : you must provide "int32, float32, string, boolean or struct"`
+}
+
+type TENANT struct {
+	RATIO float32 `default:"0.5"`
+	LIMIT int64   // want `field "CONFIG.TENANTS.LIMIT" : type "int64" is not supported : you must provide "int32, float32, string, boolean or struct"`
+}
+
+type CONFIG struct {
+	DB       DB
+	DATABASE string `envh:"DB"`                            // want `field "CONFIG.DATABASE" : key "DB" is already mapped to field "CONFIG.DB"`
+	LEVEL    string `validate:"oneof=debug info,whatever"` // want `field "CONFIG.LEVEL" : tag validate:"oneof=debug info,whatever" is invalid : rule "whatever" doesn't exist`
+	MIN      int    `validate:"ltfield=MAXIMUM"`           // want `field "CONFIG.MIN" : tag validate:"ltfield=MAXIMUM" is invalid : field "MAXIMUM" doesn't exist`
+	MAX      int    `validate:"gtfield="`                  // want `field "CONFIG.MAX" : tag validate:"gtfield=" is invalid : rule "gtfield" requires a field name as parameter`
+	TOKEN    string `envh:",private"`                      // want `field "CONFIG.TOKEN" : tag envh:",private" is invalid : option "private" doesn't exist`
+	ENABLED  bool   `default:"yes"`                        // want `field "CONFIG.ENABLED" : default value "yes" can't be converted to type "bool"`
+	TENANTS  map[string]TENANT
+	LABELS   map[string]string // want `field "CONFIG.LABELS" : type "map\[string\]string" is not supported : you must provide "int32, float32, string, boolean or struct"`
+	STORAGE  Storage           // want `field "CONFIG.STORAGE" : type "Storage" is not supported : you must provide "int32, float32, string, boolean or struct"`
+	internal chan int
+}
+
+type WALKED struct {
+	DB struct {
+		HOST string
+		URL  string
+	}
+	MAP map[string]string
+}
+
+func (w *WALKED) Walk(tree *envh.EnvTree, keyChain []string) (bool, error) {
+	switch strings.Join(keyChain, "_") {
+	case "WALKED_DB_URL", "WALKED_MAP":
+		return true, nil
+	case "WALKED_DB_URI": // want `key "WALKED_DB_URI" doesn't match any field of "WALKED"`
+		return true, nil
+	}
+
+	return false, nil
+}
+
+type DECODED struct {
+	TIMEOUT time.Duration
+	STORAGE Storage
+}
+
+type FLAT struct {
+	URL string `env:"DATABASE_URL"`
+	DB  struct {
+		URL string `env:"DATABASE_URL"` // want `field "FLAT.DB.URL" : key "DATABASE_URL" is already mapped to field "FLAT.URL"`
+	}
+	PORT int `env:"PORT" default:"8080"`
+}
+
+type UPPER struct {
+	Host string
+	HOST string // want `field "UPPER.HOST" : key "HOST" is already mapped to field "UPPER.Host"`
+}
+
+func populate(tree envh.EnvTree, env envh.Env) {
+	_ = tree.PopulateStruct(&CONFIG{})
+	_ = tree.PopulateStructWithStrictMode(&CONFIG{})
+	_ = tree.PopulateStruct(&WALKED{})
+	_ = tree.PopulateStruct(CONFIG{}) // want `PopulateStruct expects a pointer to struct, got "CONFIG"`
+	_ = tree.Populate(&DECODED{}, envh.WithDecoder(time.ParseDuration), envh.WithVariants("TYPE", map[string]Storage{}))
+	_ = tree.Populate(&UPPER{}, envh.WithNamingStrategy(envh.UpperCaseNaming))
+	_ = env.Populate(&FLAT{})
+}
@@ -0,0 +1,38 @@
+// Package envh is a stub of envh API used by analyzer tests
+package envh
+
+type EnvTree struct{}
+
+func (e EnvTree) PopulateStruct(structure interface{}) error { return nil }
+
+func (e EnvTree) PopulateStructWithStrictMode(structure interface{}) error { return nil }
+
+func (e EnvTree) PopulateStructAt(structure interface{}, keyChain ...string) error { return nil }
+
+func (e EnvTree) Populate(structure interface{}, options ...PopulateOption) error { return nil }
+
+type Env struct{}
+
+func (e Env) Populate(structure interface{}, options ...PopulateOption) error { return nil }
+
+type PopulateOption func()
+
+func WithStrictMode() PopulateOption { return nil }
+
+func WithTagName(name string) PopulateOption { return nil }
+
+func WithRootKey(keyChain ...string) PopulateOption { return nil }
+
+func WithNamingStrategy(naming func(string) string) PopulateOption { return nil }
+
+func UpperCaseNaming(fieldName string) string { return fieldName }
+
+func WithDecoder[T any](decode func(value string) (T, error)) PopulateOption { return nil }
+
+func WithVariants[I any](discriminator string, types map[string]I) PopulateOption { return nil }
+
+func RegisterDecoder[T any](decode func(value string) (T, error)) {}
+
+type Secret[T any] struct {
+	value T
+}
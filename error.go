@@ -1,22 +1,59 @@
 package envh
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 )
 
+// ErrVariableNotFound is a sentinel error matching any VariableNotFoundError
+// when using errors.Is
+var ErrVariableNotFound = errors.New("variable not found")
+
+// ErrNodeNotFound is a sentinel error matching any NodeNotFoundError
+// when using errors.Is
+var ErrNodeNotFound = errors.New("node not found")
+
+// ErrWrongType is a sentinel error matching any WrongTypeError
+// when using errors.Is
+var ErrWrongType = errors.New("wrong type")
+
+// ErrTypeUnsupported is a sentinel error matching any TypeUnsupported
+// when using errors.Is
+var ErrTypeUnsupported = errors.New("type unsupported")
+
+// ErrValidation is a sentinel error matching any ValidationError
+// when using errors.Is
+var ErrValidation = errors.New("validation failed")
+
+// ErrUnknownKey is a sentinel error matching any UnknownKeyError
+// when using errors.Is
+var ErrUnknownKey = errors.New("unknown key")
+
 // VariableNotFoundError is triggered when environment variable cannot be found
 type VariableNotFoundError struct {
+	KeyChain []string
+	Variable string
 }
 
 // Error dump error
 func (e VariableNotFoundError) Error() string {
-	return "Variable not found"
+	if e.Variable == "" {
+		return "Variable not found"
+	}
+
+	return fmt.Sprintf(`Variable "%s" not found`, e.Variable)
+}
+
+// Is reports whether target is ErrVariableNotFound
+func (e VariableNotFoundError) Is(target error) bool {
+	return target == ErrVariableNotFound
 }
 
 // NodeNotFoundError is triggered when tree node cannot be found
 type NodeNotFoundError struct {
 	KeyChain []string
+	Variable string
 }
 
 // Error dump error
@@ -24,24 +61,298 @@ func (e NodeNotFoundError) Error() string {
 	return fmt.Sprintf(`No node found at path "%s"`, strings.Join(e.KeyChain, " -> "))
 }
 
-// WrongTypeError is triggered when we try to convert variable to a wrong type
+// Is reports whether target is ErrNodeNotFound
+func (e NodeNotFoundError) Is(target error) bool {
+	return target == ErrNodeNotFound
+}
+
+// WrongTypeError is triggered when we try to convert variable to a wrong type,
+// Err is the underlying conversion error. Value is redacted when error is dumped
+// if Secret is true or if variable name is sensitive
 type WrongTypeError struct {
-	Value interface{}
-	Type  string
+	Value    interface{}
+	Type     string
+	KeyChain []string
+	Variable string
+	Err      error
+	Secret   bool
 }
 
 // Error dump error
 func (e WrongTypeError) Error() string {
-	return fmt.Sprintf(`Value "%s" can't be converted to type "%s"`, e.Value, e.Type)
+	value := redact(e.Value, e.Variable, e.Secret)
+
+	if e.Variable == "" {
+		return fmt.Sprintf(`Value "%s" can't be converted to type "%s"`, value, e.Type)
+	}
+
+	return fmt.Sprintf(`Value "%s" of variable "%s" can't be converted to type "%s"`, value, e.Variable, e.Type)
+}
+
+// Unwrap returns underlying conversion error
+func (e WrongTypeError) Unwrap() error {
+	return e.Err
 }
 
-// TypeUnsupported is triggered when a type isn't supported
+// Is reports whether target is ErrWrongType
+func (e WrongTypeError) Is(target error) bool {
+	return target == ErrWrongType
+}
+
+// TypeUnsupported is triggered when a type isn't supported, Variable
+// is name of the variable the value would be read from
 type TypeUnsupported struct {
 	ActualType   string
 	RequiredType string
+	KeyChain     []string
+	Variable     string
 }
 
 // Error dump error
 func (e TypeUnsupported) Error() string {
-	return fmt.Sprintf(`Type "%s" is not supported : you must provide "%s"`, e.ActualType, e.RequiredType)
+	if e.Variable == "" {
+		return fmt.Sprintf(`Type "%s" is not supported : you must provide "%s"`, e.ActualType, e.RequiredType)
+	}
+
+	return fmt.Sprintf(`Type "%s" of variable "%s" is not supported : you must provide "%s"`, e.ActualType, e.Variable, e.RequiredType)
+}
+
+// Is reports whether target is ErrTypeUnsupported
+func (e TypeUnsupported) Is(target error) bool {
+	return target == ErrTypeUnsupported
+}
+
+// ValidationError is triggered when a value doesn't satisfy
+// a rule defined in a validate struct tag, Value is nil
+// when rule failed because variable is not defined.
+// Value is redacted when error is dumped if Secret is true
+// or if variable name is sensitive
+type ValidationError struct {
+	Value    interface{}
+	Rule     string
+	Param    string
+	Message  string
+	KeyChain []string
+	Variable string
+	Secret   bool
+}
+
+// Error dump error
+func (e ValidationError) Error() string {
+	if e.Value == nil {
+		return fmt.Sprintf(`Variable "%s" is invalid : %s`, e.Variable, e.Message)
+	}
+
+	value := redact(e.Value, e.Variable, e.Secret)
+
+	if e.Variable == "" {
+		return fmt.Sprintf(`Value "%v" is invalid : %s`, value, e.Message)
+	}
+
+	return fmt.Sprintf(`Value "%v" of variable "%s" is invalid : %s`, value, e.Variable, e.Message)
+}
+
+// Is reports whether target is ErrValidation
+func (e ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// TagError is triggered when a struct tag is malformed
+type TagError struct {
+	Tag    string
+	Value  string
+	Reason string
+}
+
+// Error dump error
+func (e TagError) Error() string {
+	return fmt.Sprintf(`Tag %s:"%s" is invalid : %s`, e.Tag, e.Value, e.Reason)
+}
+
+// UnknownKeyError is triggered when a variable defined below
+// a struct key doesn't match any field, Suggestion is the closest
+// expected variable name if any
+type UnknownKeyError struct {
+	KeyChain   []string
+	Variable   string
+	Suggestion string
+}
+
+// Error dump error
+func (e UnknownKeyError) Error() string {
+	if e.Suggestion == "" {
+		return fmt.Sprintf(`Variable "%s" doesn't match any field`, e.Variable)
+	}
+
+	return fmt.Sprintf(`Variable "%s" doesn't match any field, did you mean "%s" ?`, e.Variable, e.Suggestion)
+}
+
+// Is reports whether target is ErrUnknownKey
+func (e UnknownKeyError) Is(target error) bool {
+	return target == ErrUnknownKey
+}
+
+// FieldError is triggered when a struct field can't be populated,
+// it wraps underlying error and keeps key chain and
+// go field path (e.g. "DB.PORT") leading to the field,
+// path is empty when error is triggered by root struct
+type FieldError struct {
+	KeyChain []string
+	Path     string
+	Err      error
+}
+
+// Error dump error
+func (e FieldError) Error() string {
+	if e.Path == "" && len(e.KeyChain) == 0 {
+		return fmt.Sprintf(`Struct : %s`, e.Err)
+	}
+
+	if e.Path == "" {
+		return fmt.Sprintf(`Struct "%s" : %s`, strings.Join(e.KeyChain, " -> "), e.Err)
+	}
+
+	return fmt.Sprintf(`Field "%s" : %s`, e.Path, e.Err)
+}
+
+// Unwrap returns underlying error
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// PopulateError is triggered when one or several fields
+// can't be populated, it gathers every field error encountered
+// and every unknown variable when keys are checked
+type PopulateError struct {
+	Errors      []FieldError
+	UnknownKeys []UnknownKeyError
+}
+
+// Error dump error
+func (e PopulateError) Error() string {
+	lines := []string{fmt.Sprintf("%d error(s) occurred while populating struct :", len(e.Errors)+len(e.UnknownKeys))}
+
+	for _, err := range e.Errors {
+		lines = append(lines, "  - "+err.Error())
+	}
+
+	for _, err := range e.UnknownKeys {
+		lines = append(lines, "  - "+err.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns all field errors and unknown key errors
+func (e PopulateError) Unwrap() []error {
+	errs := []error{}
+
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+
+	for _, err := range e.UnknownKeys {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// ParseError gathers every error occurred while
+// parsing variables registered in a VarSet
+type ParseError struct {
+	Errors []error
+}
+
+// Error dump error
+func (e ParseError) Error() string {
+	lines := []string{fmt.Sprintf("%d error(s) occurred while parsing variables :", len(e.Errors))}
+
+	for _, err := range e.Errors {
+		lines = append(lines, "  - "+err.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns all errors
+func (e ParseError) Unwrap() []error {
+	return e.Errors
+}
+
+// ContractViolation is triggered when a declared variable
+// doesn't fulfill its declaration
+type ContractViolation struct {
+	Owner    string
+	Variable string
+	Err      error
+}
+
+// Error dump error
+func (e ContractViolation) Error() string {
+	if e.Owner == "" {
+		return e.Err.Error()
+	}
+
+	return fmt.Sprintf(`%s (declared by "%s")`, e.Err.Error(), e.Owner)
+}
+
+// Unwrap returns underlying error
+func (e ContractViolation) Unwrap() error {
+	return e.Err
+}
+
+// ContractError gathers every violation of declared variables
+type ContractError struct {
+	Violations []ContractViolation
+}
+
+// Error dump error
+func (e ContractError) Error() string {
+	lines := []string{fmt.Sprintf("%d error(s) occurred while validating contract :", len(e.Violations))}
+
+	for _, err := range e.Violations {
+		lines = append(lines, "  - "+err.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns all violations
+func (e ContractError) Unwrap() []error {
+	errs := []error{}
+
+	for _, err := range e.Violations {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// SchemaError gathers every variable of a tree
+// which doesn't satisfy a JSON Schema
+type SchemaError struct {
+	Errors []ValidationError
+}
+
+// Error dump error
+func (e SchemaError) Error() string {
+	lines := []string{fmt.Sprintf("%d error(s) occurred while validating schema :", len(e.Errors))}
+
+	for _, err := range e.Errors {
+		lines = append(lines, "  - "+err.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Unwrap returns all validation errors
+func (e SchemaError) Unwrap() []error {
+	errs := []error{}
+
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+
+	return errs
 }
@@ -0,0 +1,37 @@
+// Command gen copies tagspec package into envhcheck module, envhcheck
+// lives in its own module and can't import it but must parse tags like envh does
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+)
+
+const header = "// Code generated by internal/tagspec/gen from internal/tagspec/tagspec.go; DO NOT EDIT.\n\n"
+
+var generateDirective = regexp.MustCompile(`(?m)^//go:generate .*\n\n`)
+
+// generate returns content of the copy, go:generate
+// directive is dropped as it only makes sense in envh module
+func generate(src []byte) []byte {
+	return append([]byte(header), generateDirective.ReplaceAll(src, []byte{})...)
+}
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: gen <output>")
+		os.Exit(1)
+	}
+
+	src, err := os.ReadFile("tagspec.go")
+
+	if err == nil {
+		err = os.WriteFile(os.Args[1], generate(src), 0o644)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCopyIsUpToDate(t *testing.T) {
+	src, err := os.ReadFile("../tagspec.go")
+
+	assert.NoError(t, err)
+
+	copied, err := os.ReadFile("../../../envhcheck/internal/tagspec/tagspec.go")
+
+	assert.NoError(t, err)
+	assert.Equal(t, string(generate(src)), string(copied), `envhcheck copy is outdated, run "go generate ./internal/tagspec"`)
+}
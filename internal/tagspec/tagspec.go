@@ -0,0 +1,159 @@
+// Package tagspec describes syntax of struct tags understood by envh,
+// it's shared by the library, envhgen and envhcheck so they can't disagree
+// about keys, options and validation rules. envhcheck lives in its own
+// module and gets a copy generated with go generate
+package tagspec
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+//go:generate go run ./gen ../../envhcheck/internal/tagspec/tagspec.go
+
+// SecretOption marks a field as secret in an envh tag, for instance `envh:"PASSWORD,secret"`,
+// it's the only option accepted after keys
+const SecretOption = "secret"
+
+// Rule is a single validation rule extracted from a validate tag,
+// for instance "min=1" gives a rule named "min" with "1" as parameter
+type Rule struct {
+	Name  string
+	Param string
+}
+
+// RuleSpec describes how a validation rule is written
+type RuleSpec struct {
+	// HasParam is true if rule requires a parameter
+	HasParam bool
+	// CrossField is true if parameter is name of a sibling field
+	CrossField bool
+}
+
+// Rules lists every validation rule
+var Rules = map[string]RuleSpec{
+	"min":              {true, false},
+	"max":              {true, false},
+	"len":              {true, false},
+	"oneof":            {true, false},
+	"regex":            {true, false},
+	"url":              {false, false},
+	"hostname":         {false, false},
+	"ip":               {false, false},
+	"port":             {false, false},
+	"nonempty":         {false, false},
+	"required_if":      {true, true},
+	"required_with":    {true, true},
+	"required_without": {true, true},
+	"excluded_with":    {true, true},
+	"gtfield":          {true, true},
+	"gtefield":         {true, true},
+	"ltfield":          {true, true},
+	"ltefield":         {true, true},
+}
+
+// ParseKeys returns candidate keys separated by pipes, empty ones are dropped
+func ParseKeys(value string) []string {
+	keys := []string{}
+
+	for _, key := range strings.Split(value, "|") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// ParseRules extracts rules from a validate tag, rules are separated
+// by commas, a comma belonging to a rule parameter must be escaped with a backslash.
+// Error message describes the first malformed rule
+func ParseRules(tag string) ([]Rule, error) {
+	rules := []Rule{}
+
+	if tag == "" {
+		return rules, nil
+	}
+
+	for _, chunk := range SplitEscaped(tag, ',') {
+		r := Rule{}
+		parts := strings.SplitN(chunk, "=", 2)
+		r.Name = strings.TrimSpace(parts[0])
+
+		if len(parts) == 2 {
+			r.Param = parts[1]
+		}
+
+		spec, ok := Rules[r.Name]
+
+		switch {
+		case !ok:
+			return []Rule{}, fmt.Errorf(`rule "%s" doesn't exist`, r.Name)
+		case spec.CrossField && strings.TrimSpace(r.Param) == "":
+			return []Rule{}, fmt.Errorf(`rule "%s" requires a field name as parameter`, r.Name)
+		case spec.HasParam && len(parts) != 2:
+			return []Rule{}, fmt.Errorf(`rule "%s" requires a parameter`, r.Name)
+		case !spec.HasParam && len(parts) == 2:
+			return []Rule{}, fmt.Errorf(`rule "%s" doesn't accept any parameter`, r.Name)
+		}
+
+		rules = append(rules, r)
+	}
+
+	return rules, nil
+}
+
+// CheckValue returns name of the type value can't be converted to,
+// an empty string is returned if it can be converted like envh does
+func CheckValue(value string, kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int:
+		if _, err := strconv.Atoi(value); err != nil {
+			return "int"
+		}
+	case reflect.Float32:
+		if _, err := strconv.ParseFloat(value, 32); err != nil {
+			return "float"
+		}
+	case reflect.Bool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return "bool"
+		}
+	}
+
+	return ""
+}
+
+// SplitEscaped splits s around sep, a separator preceded by a backslash is kept
+func SplitEscaped(s string, sep rune) []string {
+	chunks := []string{}
+	current := []rune{}
+	escaped := false
+
+	for _, c := range s {
+		switch {
+		case escaped:
+			if c != sep {
+				current = append(current, '\\')
+			}
+
+			current = append(current, c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == sep:
+			chunks = append(chunks, string(current))
+			current = []rune{}
+		default:
+			current = append(current, c)
+		}
+	}
+
+	if escaped {
+		current = append(current, '\\')
+	}
+
+	return append(chunks, string(current))
+}
@@ -0,0 +1,59 @@
+package tagspec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseKeys(t *testing.T) {
+	assert.Equal(t, []string{"DATABASE_URL", "DB_URL"}, ParseKeys(" DATABASE_URL | DB_URL "))
+	assert.Equal(t, []string{}, ParseKeys(""))
+	assert.Equal(t, []string{"URL"}, ParseKeys("|URL|"))
+}
+
+func TestParseRules(t *testing.T) {
+	type g struct {
+		tag   string
+		rules []Rule
+		err   string
+	}
+
+	tests := []g{
+		{"", []Rule{}, ""},
+		{`min=1,regex=^a\,b$`, []Rule{{"min", "1"}, {"regex", "^a,b$"}}, ""},
+		{"gtfield=MIN", []Rule{{"gtfield", "MIN"}}, ""},
+		{"whatever", []Rule{}, `rule "whatever" doesn't exist`},
+		{"max", []Rule{}, `rule "max" requires a parameter`},
+		{"gtfield= ", []Rule{}, `rule "gtfield" requires a field name as parameter`},
+		{"url=1", []Rule{}, `rule "url" doesn't accept any parameter`},
+	}
+
+	for _, test := range tests {
+		rules, err := ParseRules(test.tag)
+
+		if test.err != "" {
+			assert.EqualError(t, err, test.err)
+		} else {
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, test.rules, rules)
+	}
+}
+
+func TestCheckValue(t *testing.T) {
+	assert.Equal(t, "", CheckValue("1", reflect.Int))
+	assert.Equal(t, "int", CheckValue("1.5", reflect.Int))
+	assert.Equal(t, "", CheckValue("1.5", reflect.Float32))
+	assert.Equal(t, "float", CheckValue("half", reflect.Float32))
+	assert.Equal(t, "", CheckValue("true", reflect.Bool))
+	assert.Equal(t, "bool", CheckValue("yes", reflect.Bool))
+	assert.Equal(t, "", CheckValue("whatever", reflect.String))
+}
+
+func TestSplitEscaped(t *testing.T) {
+	assert.Equal(t, []string{"a", "b,c", `d\e`}, SplitEscaped(`a,b\,c,d\e`, ','))
+	assert.Equal(t, []string{`a\`}, SplitEscaped(`a\`, ','))
+}
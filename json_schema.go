@@ -0,0 +1,263 @@
+package envh
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// JSONSchemaDraft is the JSON Schema version generated schemas comply with
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// JSONSchema is a JSON Schema document, or a part of it,
+// it can be marshaled with encoding/json
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Type                 JSONSchemaType         `json:"type,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Enum                 []interface{}          `json:"enum,omitempty"`
+	Default              interface{}            `json:"default,omitempty"`
+	Minimum              *float64               `json:"minimum,omitempty"`
+	Maximum              *float64               `json:"maximum,omitempty"`
+	MinLength            *int                   `json:"minLength,omitempty"`
+	MaxLength            *int                   `json:"maxLength,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	AllOf                []*JSONSchema          `json:"allOf,omitempty"`
+	Not                  *JSONSchema            `json:"not,omitempty"`
+	WriteOnly            bool                   `json:"writeOnly,omitempty"`
+	Deprecated           bool                   `json:"deprecated,omitempty"`
+}
+
+// JSONSchemaType holds types allowed by a schema, a single type
+// is marshaled as a string and several ones as an array
+type JSONSchemaType []string
+
+// MarshalJSON encodes a single type as a string
+func (t JSONSchemaType) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+
+	return json.Marshal([]string(t))
+}
+
+// UnmarshalJSON decodes a type given as a string or as an array of strings
+func (t *JSONSchemaType) UnmarshalJSON(data []byte) error {
+	var typ string
+
+	if err := json.Unmarshal(data, &typ); err == nil {
+		*t = JSONSchemaType{typ}
+
+		return nil
+	}
+
+	return json.Unmarshal(data, (*[]string)(t))
+}
+
+// has returns true if typ is one of the types allowed
+func (t JSONSchemaType) has(typ string) bool {
+	for _, v := range t {
+		if v == typ {
+			return true
+		}
+	}
+
+	return false
+}
+
+// NewJSONSchema builds a JSON Schema describing the nested object shape
+// of described variables, every key of a key chain is an object property,
+// so it matches EnvTree layout. Entries of maps of structs are described
+// with additionalProperties, variables of variants are never required
+// and validate rules are turned into keywords when JSON Schema has an equivalent.
+// Default values of secret variables and of variables whose name is sensitive are omitted
+func NewJSONSchema(descriptions []VariableDescription) *JSONSchema {
+	root := newObjectJSONSchema()
+	root.Schema = JSONSchemaDraft
+
+	for _, d := range descriptions {
+		if len(d.KeyChain) == 0 {
+			continue
+		}
+
+		parents := []*JSONSchema{}
+		current := root
+
+		for _, key := range d.KeyChain[:len(d.KeyChain)-1] {
+			parents = append(parents, current)
+			current = current.child(key)
+		}
+
+		parents = append(parents, current)
+		current.Properties[d.KeyChain[len(d.KeyChain)-1]] = newLeafJSONSchema(d)
+
+		if !d.Required || d.Variant != "" {
+			continue
+		}
+
+		// a required variable makes every object leading to it required,
+		// until a map of structs is reached as it can be empty
+		for i := len(d.KeyChain) - 1; i >= 0 && d.KeyChain[i] != mapEntryKey; i-- {
+			parents[i].require(d.KeyChain[i])
+		}
+	}
+
+	return root
+}
+
+func newObjectJSONSchema() *JSONSchema {
+	return &JSONSchema{Type: JSONSchemaType{"object"}, Properties: map[string]*JSONSchema{}}
+}
+
+// child returns schema of a property, it's created if it doesn't exist
+func (s *JSONSchema) child(key string) *JSONSchema {
+	if key == mapEntryKey {
+		if s.AdditionalProperties == nil {
+			s.AdditionalProperties = newObjectJSONSchema()
+		}
+
+		return s.AdditionalProperties
+	}
+
+	if _, ok := s.Properties[key]; !ok {
+		s.Properties[key] = newObjectJSONSchema()
+	}
+
+	return s.Properties[key]
+}
+
+func (s *JSONSchema) require(key string) {
+	for _, k := range s.Required {
+		if k == key {
+			return
+		}
+	}
+
+	s.Required = append(s.Required, key)
+}
+
+func newLeafJSONSchema(d VariableDescription) *JSONSchema {
+	s := &JSONSchema{
+		Type:        JSONSchemaType{jsonSchemaType(d.Kind)},
+		Description: d.Description,
+		WriteOnly:   isSecret(d.Name, d.Secret),
+		Deprecated:  d.Deprecated,
+	}
+
+	if d.HasDefault && !s.WriteOnly {
+		s.Default = typedValue(d.Default, d.Kind)
+	}
+
+	rules, err := parseValidationTag(d.Rules)
+
+	if err != nil {
+		return s
+	}
+
+	for _, r := range rules {
+		s.addRule(r, d.Kind)
+	}
+
+	return s
+}
+
+// addRule turns a validate rule into JSON Schema keywords
+func (s *JSONSchema) addRule(r rule, kind reflect.Kind) {
+	isNumber := kind == reflect.Int || kind == reflect.Float32
+
+	switch r.name {
+	case "min", "max":
+		bound, err := strconv.ParseFloat(r.param, 64)
+
+		if err != nil {
+			return
+		}
+
+		switch {
+		case isNumber && r.name == "min":
+			s.Minimum = &bound
+		case isNumber:
+			s.Maximum = &bound
+		case r.name == "min":
+			length := int(bound)
+			s.MinLength = &length
+		default:
+			length := int(bound)
+			s.MaxLength = &length
+		}
+	case "len":
+		if length, err := strconv.Atoi(r.param); err == nil {
+			s.MinLength = &length
+			s.MaxLength = &length
+		}
+	case "oneof":
+		for _, choice := range strings.Fields(r.param) {
+			s.Enum = append(s.Enum, typedValue(choice, kind))
+		}
+	case "regex":
+		s.Pattern = r.param
+	case "url":
+		s.Format = "uri"
+	case "hostname":
+		s.Format = "hostname"
+	case "ip":
+		s.AnyOf = []*JSONSchema{{Format: "ipv4"}, {Format: "ipv6"}}
+	case "port":
+		if isNumber {
+			min, max := float64(1), float64(65535)
+			s.Minimum = &min
+			s.Maximum = &max
+		}
+	case "nonempty":
+		// kept apart so other rules can't override it
+		length := 1
+		s.AllOf = append(s.AllOf, &JSONSchema{MinLength: &length, Pattern: `\S`})
+	}
+}
+
+func jsonSchemaType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Int:
+		return "integer"
+	case reflect.Float32:
+		return "number"
+	case reflect.Bool:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// typedValue converts a value to kind it has in a JSON document,
+// value is kept as a string if it can't be converted
+func typedValue(value string, kind reflect.Kind) interface{} {
+	fun := func() (string, bool) {
+		return value, true
+	}
+
+	var v interface{}
+	var err error
+
+	switch kind {
+	case reflect.Int:
+		v, err = getInt(fun, varRef{})
+	case reflect.Float32:
+		v, err = getFloat(fun, varRef{})
+	case reflect.Bool:
+		v, err = getBool(fun, varRef{})
+	default:
+		return value
+	}
+
+	if err != nil {
+		return value
+	}
+
+	return v
+}
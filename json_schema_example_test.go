@@ -0,0 +1,110 @@
+package envh
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+func ExampleNewJSONSchema() {
+	type APP struct {
+		DB struct {
+			HOST string `description:"database host" validate:"hostname"`
+			PORT int    `default:"5432" validate:"port"`
+		}
+	}
+
+	descriptions, err := Describe(APP{}, "_", WithStrictMode())
+
+	if err != nil {
+		return
+	}
+
+	b, err := json.MarshalIndent(NewJSONSchema(descriptions), "", "  ")
+
+	if err != nil {
+		return
+	}
+
+	fmt.Println(string(b))
+	// Output:
+	// {
+	//   "$schema": "https://json-schema.org/draft/2020-12/schema",
+	//   "type": "object",
+	//   "properties": {
+	//     "APP": {
+	//       "type": "object",
+	//       "properties": {
+	//         "DB": {
+	//           "type": "object",
+	//           "properties": {
+	//             "HOST": {
+	//               "type": "string",
+	//               "description": "database host",
+	//               "format": "hostname"
+	//             },
+	//             "PORT": {
+	//               "type": "integer",
+	//               "default": 5432,
+	//               "minimum": 1,
+	//               "maximum": 65535
+	//             }
+	//           },
+	//           "required": [
+	//             "HOST"
+	//           ]
+	//         }
+	//       },
+	//       "required": [
+	//         "DB"
+	//       ]
+	//     }
+	//   },
+	//   "required": [
+	//     "APP"
+	//   ]
+	// }
+}
+
+func ExampleEnvTree_ValidateJSONSchema() {
+	os.Clearenv()
+	setEnv("APP_DB_HOST", "localhost")
+	setEnv("APP_DB_PORT", "http")
+
+	schema, err := ParseJSONSchema(strings.NewReader(`{
+		"type": "object",
+		"properties": {
+			"APP": {
+				"type": "object",
+				"required": ["DB", "LEVEL"],
+				"properties": {
+					"DB": {
+						"type": "object",
+						"properties": {
+							"HOST": {"type": "string", "format": "hostname"},
+							"PORT": {"type": "integer", "minimum": 1, "maximum": 65535}
+						}
+					},
+					"LEVEL": {"enum": ["debug", "info"]}
+				}
+			}
+		}
+	}`))
+
+	if err != nil {
+		return
+	}
+
+	tree, err := NewEnvTree("^APP", "_")
+
+	if err != nil {
+		return
+	}
+
+	fmt.Println(tree.ValidateJSONSchema(schema))
+	// Output:
+	// 2 error(s) occurred while validating schema :
+	//   - Variable "APP_LEVEL" is invalid : is required by schema
+	//   - Value "http" of variable "APP_DB_PORT" is invalid : must be of type integer
+}
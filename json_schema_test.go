@@ -0,0 +1,130 @@
+package envh
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewJSONSchema(t *testing.T) {
+	type TENANT struct {
+		HOST string `validate:"hostname"`
+		PORT int    `validate:"port"`
+	}
+
+	type SCHEMA struct {
+		DB struct {
+			URL      string  `validate:"url" description:"database url"`
+			POOL     int     `default:"10" validate:"min=1,max=100"`
+			RATIO    float32 `default:"0.5"`
+			PASSWORD string  `envh:",secret" default:"hunter2"`
+		}
+		LEVEL   string `validate:"oneof=debug info,nonempty"`
+		CODE    string `validate:"len=3,regex=^[A-Z]+$,nonempty"`
+		NAME    string `validate:"nonempty,regex=^a*$"`
+		DEBUG   bool   `default:"true" deprecated:"use LEVEL"`
+		IP      string `validate:"ip"`
+		TENANTS map[string]TENANT
+	}
+
+	descriptions, err := Describe(SCHEMA{}, "_", WithStrictMode())
+
+	assert.NoError(t, err)
+
+	b, err := json.Marshal(NewJSONSchema(descriptions))
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"type": "object",
+		"required": ["SCHEMA"],
+		"properties": {
+			"SCHEMA": {
+				"type": "object",
+				"required": ["DB", "LEVEL", "CODE", "NAME", "IP"],
+				"properties": {
+					"DB": {
+						"type": "object",
+						"required": ["URL"],
+						"properties": {
+							"URL": {"type": "string", "description": "database url", "format": "uri"},
+							"POOL": {"type": "integer", "default": 10, "minimum": 1, "maximum": 100},
+							"RATIO": {"type": "number", "default": 0.5},
+							"PASSWORD": {"type": "string", "writeOnly": true}
+						}
+					},
+					"LEVEL": {"type": "string", "enum": ["debug", "info"], "allOf": [{"minLength": 1, "pattern": "\\S"}]},
+					"CODE": {"type": "string", "minLength": 3, "maxLength": 3, "pattern": "^[A-Z]+$", "allOf": [{"minLength": 1, "pattern": "\\S"}]},
+					"NAME": {"type": "string", "pattern": "^a*$", "allOf": [{"minLength": 1, "pattern": "\\S"}]},
+					"DEBUG": {"type": "boolean", "default": true, "deprecated": true},
+					"IP": {"type": "string", "anyOf": [{"format": "ipv4"}, {"format": "ipv6"}]},
+					"TENANTS": {
+						"type": "object",
+						"additionalProperties": {
+							"type": "object",
+							"required": ["HOST", "PORT"],
+							"properties": {
+								"HOST": {"type": "string", "format": "hostname"},
+								"PORT": {"type": "integer", "minimum": 1, "maximum": 65535}
+							}
+						}
+					}
+				}
+			}
+		}
+	}`, string(b))
+}
+
+func TestNewJSONSchemaWithVariants(t *testing.T) {
+	type VARIANTSCHEMA struct {
+		STORAGE storage
+	}
+
+	descriptions, err := Describe(VARIANTSCHEMA{}, "_", WithStrictMode(), variantsOption())
+
+	assert.NoError(t, err)
+
+	schema := NewJSONSchema(descriptions)
+	storageSchema := schema.Properties["VARIANTSCHEMA"].Properties["STORAGE"]
+
+	assert.Equal(t, []string{"VARIANTSCHEMA"}, schema.Required)
+	assert.Equal(t, []string{"STORAGE"}, schema.Properties["VARIANTSCHEMA"].Required)
+	assert.Equal(t, []string{"TYPE"}, storageSchema.Required)
+	assert.Equal(t, []interface{}{"fs", "s3"}, storageSchema.Properties["TYPE"].Enum)
+}
+
+func TestNewJSONSchemaWithSensitiveNames(t *testing.T) {
+	schema := NewJSONSchema([]VariableDescription{
+		{Name: "APP_API_TOKEN", KeyChain: []string{"APP", "API", "TOKEN"}, Kind: reflect.String, Default: "t0k3n", HasDefault: true},
+		{Name: "APP_MONKEY", KeyChain: []string{"APP", "MONKEY"}, Kind: reflect.String, Default: "banana", HasDefault: true},
+	})
+
+	b, err := json.Marshal(schema)
+
+	assert.NoError(t, err)
+	assert.NotContains(t, string(b), "t0k3n")
+	assert.Equal(t, &JSONSchema{Type: JSONSchemaType{"string"}, WriteOnly: true}, schema.Properties["APP"].Properties["API"].Properties["TOKEN"])
+	assert.Equal(t, &JSONSchema{Type: JSONSchemaType{"string"}, Default: "banana"}, schema.Properties["APP"].Properties["MONKEY"])
+}
+
+func TestTypedValue(t *testing.T) {
+	type g struct {
+		value    string
+		kind     reflect.Kind
+		expected interface{}
+	}
+
+	tests := []g{
+		{"12", reflect.Int, 12},
+		{"1.5", reflect.Float32, float32(1.5)},
+		{"true", reflect.Bool, true},
+		{"test", reflect.String, "test"},
+		{"test", reflect.Int, "test"},
+	}
+
+	for _, test := range tests {
+		assert.Equal(t, test.expected, typedValue(test.value, test.kind))
+	}
+}
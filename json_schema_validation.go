@@ -0,0 +1,365 @@
+package envh
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// jsonSchemaAnnotations are keywords which don't change
+// validation, they're accepted and ignored
+var jsonSchemaAnnotations = []string{"$id", "$comment", "$defs", "definitions", "title", "examples", "readOnly"}
+
+// jsonSchemaKeywords are keywords a schema can hold, a keyword changing validation
+// which isn't supported would let invalid values pass, so it's rejected
+var jsonSchemaKeywords = func() map[string]bool {
+	keywords := map[string]bool{}
+	typ := reflect.TypeOf(JSONSchema{})
+
+	for i := 0; i < typ.NumField(); i++ {
+		keywords[strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]] = true
+	}
+
+	for _, keyword := range jsonSchemaAnnotations {
+		keywords[keyword] = true
+	}
+
+	return keywords
+}()
+
+// ParseJSONSchema reads a JSON Schema document, an error is returned
+// if a keyword changing validation isn't supported by JSONSchema
+func ParseJSONSchema(r io.Reader) (*JSONSchema, error) {
+	schema := &JSONSchema{}
+
+	if err := json.NewDecoder(r).Decode(schema); err != nil {
+		return nil, err
+	}
+
+	return schema, nil
+}
+
+// UnmarshalJSON decodes a schema, boolean schemas are supported :
+// true accepts everything and false is decoded as {"not": {}}.
+// An error is returned if a keyword isn't supported
+func (s *JSONSchema) UnmarshalJSON(data []byte) error {
+	var accept bool
+
+	if err := json.Unmarshal(data, &accept); err == nil {
+		*s = JSONSchema{}
+
+		if !accept {
+			s.Not = &JSONSchema{}
+		}
+
+		return nil
+	}
+
+	keywords := map[string]json.RawMessage{}
+
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return err
+	}
+
+	names := []string{}
+
+	for name := range keywords {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !jsonSchemaKeywords[name] {
+			return fmt.Errorf(`keyword "%s" is not supported`, name)
+		}
+	}
+
+	type plainJSONSchema JSONSchema
+
+	return json.Unmarshal(data, (*plainJSONSchema)(s))
+}
+
+// ValidateJSONSchema checks tree against a JSON Schema, every key of a key chain
+// is an object property like in schemas generated by NewJSONSchema.
+// Values are strings, their type is inferred from the type expected
+// by the schema (integer, number, boolean) or from the value itself when
+// no type is defined, a value matching one of several types is valid.
+// Supported keywords are type, properties, additionalProperties,
+// required, enum, minimum, maximum, minLength, maxLength, pattern, format (uri,
+// hostname, ipv4, ipv6), anyOf, allOf and not. Every failing variable is reported
+// at once in a SchemaError, values of write only properties are redacted
+func (e EnvTree) ValidateJSONSchema(schema *JSONSchema) error {
+	errs := []ValidationError{}
+
+	validateNodeSchema(&e, e.root, []string{}, schema, &errs)
+
+	if len(errs) > 0 {
+		return SchemaError{errs}
+	}
+
+	return nil
+}
+
+func (s *JSONSchema) isObject() bool {
+	return s.Type.has("object") || s.Properties != nil || s.AdditionalProperties != nil || len(s.Required) > 0
+}
+
+// rejectsEverything returns true for the false boolean schema
+func (s *JSONSchema) rejectsEverything() bool {
+	return s.Not != nil && reflect.DeepEqual(*s.Not, JSONSchema{})
+}
+
+func validateNodeSchema(tree *EnvTree, n *node, keyChain []string, schema *JSONSchema, errs *[]ValidationError) {
+	ref := tree.ref(keyChain)
+
+	if schema.rejectsEverything() {
+		*errs = append(*errs, ValidationError{nil, "not", "", "is not allowed by schema", ref.keyChain, ref.name, false})
+
+		return
+	}
+
+	if schema.isObject() {
+		validateObjectSchema(tree, n, keyChain, schema, errs)
+
+		return
+	}
+
+	if !n.hasValue {
+		*errs = append(*errs, ValidationError{nil, "type", strings.Join(schema.Type, " "), "must be a value, not an object", ref.keyChain, ref.name, schema.WriteOnly})
+
+		return
+	}
+
+	if err := validateValueSchema(n.value, ref, schema); err != nil {
+		*errs = append(*errs, *err)
+	}
+}
+
+func validateObjectSchema(tree *EnvTree, n *node, keyChain []string, schema *JSONSchema, errs *[]ValidationError) {
+	children := map[string]*node{}
+	keys := []string{}
+
+	for _, child := range n.children {
+		children[child.key] = child
+		keys = append(keys, child.key)
+	}
+
+	for _, key := range schema.Required {
+		if _, ok := children[key]; !ok {
+			ref := tree.ref(append(append([]string{}, keyChain...), key))
+			secret := schema.Properties[key] != nil && schema.Properties[key].WriteOnly
+
+			*errs = append(*errs, ValidationError{nil, "required", key, "is required by schema", ref.keyChain, ref.name, secret})
+		}
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		childKeyChain := append(append([]string{}, keyChain...), key)
+
+		if child, ok := schema.Properties[key]; ok {
+			validateNodeSchema(tree, children[key], childKeyChain, child, errs)
+		} else if schema.AdditionalProperties != nil {
+			validateNodeSchema(tree, children[key], childKeyChain, schema.AdditionalProperties, errs)
+		}
+	}
+}
+
+// validateValueSchema checks a single value and returns
+// an error on the first keyword which is not satisfied
+func validateValueSchema(raw string, ref varRef, schema *JSONSchema) *ValidationError {
+	fail := func(value interface{}, keyword string, param string, message string) *ValidationError {
+		return &ValidationError{value, keyword, param, message, ref.keyChain, ref.name, schema.WriteOnly}
+	}
+
+	value, ok := inferValue(raw, schema.Type)
+
+	if !ok {
+		return fail(raw, "type", strings.Join(schema.Type, " "), fmt.Sprintf("must be of type %s", strings.Join(schema.Type, " or ")))
+	}
+
+	if len(schema.Enum) > 0 && !isInEnum(value, schema.Enum) {
+		choices := []string{}
+
+		for _, choice := range schema.Enum {
+			choices = append(choices, fmt.Sprint(choice))
+		}
+
+		return fail(value, "enum", strings.Join(choices, " "), fmt.Sprintf(`must be one of "%s"`, strings.Join(choices, `", "`)))
+	}
+
+	val := reflect.ValueOf(value)
+	rawVal := reflect.ValueOf(raw)
+
+	for _, check := range []struct {
+		keyword string
+		bound   *float64
+		val     reflect.Value
+		kinds   []reflect.Kind
+		checker func(val reflect.Value, param string) (string, error)
+	}{
+		{"minimum", schema.Minimum, val, []reflect.Kind{reflect.Int, reflect.Float32}, checkMin},
+		{"maximum", schema.Maximum, val, []reflect.Kind{reflect.Int, reflect.Float32}, checkMax},
+		{"minLength", intBound(schema.MinLength), rawVal, []reflect.Kind{reflect.String}, checkMin},
+		{"maxLength", intBound(schema.MaxLength), rawVal, []reflect.Kind{reflect.String}, checkMax},
+	} {
+		if check.bound == nil || !isKindSupportedByRule(check.val.Kind(), ruleChecker{kinds: check.kinds}) {
+			continue
+		}
+
+		param := strconv.FormatFloat(*check.bound, 'f', -1, 64)
+
+		if message, _ := check.checker(check.val, param); message != "" {
+			return fail(value, check.keyword, param, message)
+		}
+	}
+
+	if schema.Pattern != "" {
+		message, err := checkRegex(rawVal, schema.Pattern)
+
+		if err != nil {
+			return fail(value, "pattern", schema.Pattern, fmt.Sprintf(`pattern "%s" is invalid : %s`, schema.Pattern, err))
+		}
+
+		if message != "" {
+			return fail(value, "pattern", schema.Pattern, message)
+		}
+	}
+
+	if message := checkFormat(raw, schema.Format); message != "" {
+		return fail(value, "format", schema.Format, message)
+	}
+
+	if len(schema.AnyOf) > 0 {
+		matched := false
+
+		for _, sub := range schema.AnyOf {
+			if validateValueSchema(raw, ref, sub) == nil {
+				matched = true
+
+				break
+			}
+		}
+
+		if !matched {
+			return fail(value, "anyOf", "", "must match at least one schema")
+		}
+	}
+
+	for _, sub := range schema.AllOf {
+		if err := validateValueSchema(raw, ref, sub); err != nil {
+			err.Secret = err.Secret || schema.WriteOnly
+
+			return err
+		}
+	}
+
+	if schema.Not != nil && validateValueSchema(raw, ref, schema.Not) == nil {
+		return fail(value, "not", "", "must not match schema")
+	}
+
+	return nil
+}
+
+// inferValue converts a value to the first JSON type expected it matches,
+// when no type is expected, it's guessed from the value
+func inferValue(raw string, types JSONSchemaType) (interface{}, bool) {
+	if len(types) == 0 {
+		for _, kind := range []reflect.Kind{reflect.Int, reflect.Float32, reflect.Bool} {
+			if v := typedValue(raw, kind); v != raw {
+				return v, true
+			}
+		}
+
+		return raw, true
+	}
+
+	for _, typ := range types {
+		if v, ok := inferTypedValue(raw, typ); ok {
+			return v, true
+		}
+	}
+
+	return raw, false
+}
+
+// inferTypedValue converts a value to a JSON type, a variable
+// is never null, an object or an array
+func inferTypedValue(raw string, typ string) (interface{}, bool) {
+	fun := func() (string, bool) {
+		return raw, true
+	}
+
+	switch typ {
+	case "integer":
+		v, err := getInt(fun, varRef{})
+
+		return v, err == nil
+	case "number":
+		v, err := getFloat(fun, varRef{})
+
+		return v, err == nil
+	case "boolean":
+		v, err := getBool(fun, varRef{})
+
+		return v, err == nil
+	case "string":
+		return raw, true
+	}
+
+	return raw, false
+}
+
+func isInEnum(value interface{}, enum []interface{}) bool {
+	for _, choice := range enum {
+		if fmt.Sprint(choice) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func intBound(bound *int) *float64 {
+	if bound == nil {
+		return nil
+	}
+
+	f := float64(*bound)
+
+	return &f
+}
+
+// checkFormat checks formats generated by NewJSONSchema,
+// other formats are only annotations and are ignored
+func checkFormat(raw string, format string) string {
+	ip := net.ParseIP(raw)
+
+	switch format {
+	case "uri":
+		message, _ := checkURL(reflect.ValueOf(raw), "")
+
+		return message
+	case "hostname":
+		message, _ := checkHostname(reflect.ValueOf(raw), "")
+
+		return message
+	case "ipv4":
+		if ip == nil || ip.To4() == nil {
+			return "must be a valid IPv4 address"
+		}
+	case "ipv6":
+		if ip == nil || ip.To4() != nil {
+			return "must be a valid IPv6 address"
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,276 @@
+package envh
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const validationSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"required": ["SCHEMAVAL"],
+	"properties": {
+		"SCHEMAVAL": {
+			"type": "object",
+			"required": ["DB", "LEVEL", "TOKEN"],
+			"additionalProperties": false,
+			"properties": {
+				"DB": {
+					"type": "object",
+					"required": ["HOST", "PORT", "USER"],
+					"properties": {
+						"HOST": {"type": "string", "format": "hostname"},
+						"PORT": {"type": "integer", "minimum": 1, "maximum": 65535},
+						"RATIO": {"type": "number", "maximum": 1},
+						"USER": {"type": ["string", "null"]}
+					}
+				},
+				"LEVEL": {"enum": ["debug", "info"]},
+				"CODE": {"type": "string", "minLength": 3, "maxLength": 3, "pattern": "^[A-Z]+$"},
+				"DEBUG": {"type": "boolean"},
+				"IP": {"type": "string", "anyOf": [{"format": "ipv4"}, {"format": "ipv6"}]},
+				"TOKEN": {"type": "string", "writeOnly": true, "allOf": [{"minLength": 10}]},
+				"TENANTS": {
+					"type": "object",
+					"additionalProperties": {
+						"type": "object",
+						"properties": {
+							"URL": {"type": "string", "format": "uri"}
+						}
+					}
+				}
+			}
+		}
+	}
+}`
+
+func TestValidateJSONSchema(t *testing.T) {
+	type g struct {
+		setup func()
+		err   string
+	}
+
+	tests := []g{
+		{
+			func() {
+				setEnv("SCHEMAVAL_DB_HOST", "localhost")
+				setEnv("SCHEMAVAL_DB_PORT", "5432")
+				setEnv("SCHEMAVAL_DB_RATIO", "0.5")
+				setEnv("SCHEMAVAL_DB_USER", "root")
+				setEnv("SCHEMAVAL_LEVEL", "info")
+				setEnv("SCHEMAVAL_CODE", "ABC")
+				setEnv("SCHEMAVAL_DEBUG", "true")
+				setEnv("SCHEMAVAL_IP", "::1")
+				setEnv("SCHEMAVAL_TOKEN", "0123456789")
+				setEnv("SCHEMAVAL_TENANTS_ACME_URL", "https://acme.com")
+			},
+			"",
+		},
+		{
+			func() {
+				setEnv("SCHEMAVAL_DB_HOST", "local_host")
+				setEnv("SCHEMAVAL_DB_PORT", "http")
+				setEnv("SCHEMAVAL_DB_RATIO", "1.5")
+				setEnv("SCHEMAVAL_DB_USER_NAME", "root")
+				setEnv("SCHEMAVAL_LEVEL", "trace")
+				setEnv("SCHEMAVAL_CODE", "abc")
+				setEnv("SCHEMAVAL_DEBUG", "yes")
+				setEnv("SCHEMAVAL_IP", "localhost")
+				setEnv("SCHEMAVAL_TOKEN", "012345")
+				setEnv("SCHEMAVAL_TENANTS_ACME_URL", "acme")
+				setEnv("SCHEMAVAL_TIMEOUT", "10")
+			},
+			`11 error(s) occurred while validating schema :
+  - Value "abc" of variable "SCHEMAVAL_CODE" is invalid : must match regexp "^[A-Z]+$"
+  - Value "local_host" of variable "SCHEMAVAL_DB_HOST" is invalid : must be a valid hostname
+  - Value "http" of variable "SCHEMAVAL_DB_PORT" is invalid : must be of type integer
+  - Value "1.5" of variable "SCHEMAVAL_DB_RATIO" is invalid : must be lower than or equal to 1
+  - Variable "SCHEMAVAL_DB_USER" is invalid : must be a value, not an object
+  - Value "yes" of variable "SCHEMAVAL_DEBUG" is invalid : must be of type boolean
+  - Value "localhost" of variable "SCHEMAVAL_IP" is invalid : must match at least one schema
+  - Value "trace" of variable "SCHEMAVAL_LEVEL" is invalid : must be one of "debug", "info"
+  - Value "acme" of variable "SCHEMAVAL_TENANTS_ACME_URL" is invalid : must be a valid URL
+  - Variable "SCHEMAVAL_TIMEOUT" is invalid : is not allowed by schema
+  - Value "******" of variable "SCHEMAVAL_TOKEN" is invalid : length must be greater than or equal to 10`,
+		},
+		{
+			func() {
+				setEnv("SCHEMAVAL_DB_HOST", "localhost")
+			},
+			`4 error(s) occurred while validating schema :
+  - Variable "SCHEMAVAL_LEVEL" is invalid : is required by schema
+  - Variable "SCHEMAVAL_TOKEN" is invalid : is required by schema
+  - Variable "SCHEMAVAL_DB_PORT" is invalid : is required by schema
+  - Variable "SCHEMAVAL_DB_USER" is invalid : is required by schema`,
+		},
+	}
+
+	for _, test := range tests {
+		os.Clearenv()
+		test.setup()
+
+		schema, err := ParseJSONSchema(strings.NewReader(validationSchema))
+
+		assert.NoError(t, err)
+
+		tree, err := NewEnvTree("^SCHEMAVAL", "_")
+
+		assert.NoError(t, err)
+
+		err = tree.ValidateJSONSchema(schema)
+
+		if test.err == "" {
+			assert.NoError(t, err)
+		} else {
+			assert.EqualError(t, err, test.err)
+			assert.True(t, errors.Is(err, ErrValidation))
+		}
+	}
+
+	restoreEnvs()
+}
+
+func TestValidateJSONSchemaFromSubTree(t *testing.T) {
+	os.Clearenv()
+	setEnv("SCHEMAVAL_DB_PORT", "0")
+
+	tree, err := NewEnvTree("^SCHEMAVAL", "_")
+
+	assert.NoError(t, err)
+
+	subTree, err := tree.FindSubTree("SCHEMAVAL", "DB")
+
+	assert.NoError(t, err)
+
+	minimum := float64(1)
+	err = subTree.ValidateJSONSchema(&JSONSchema{Properties: map[string]*JSONSchema{"PORT": {Type: JSONSchemaType{"integer"}, Minimum: &minimum}}})
+
+	assert.EqualError(t, err, `1 error(s) occurred while validating schema :
+  - Value "0" of variable "SCHEMAVAL_DB_PORT" is invalid : must be greater than or equal to 1`)
+	assert.Equal(t, []string{"PORT"}, err.(SchemaError).Errors[0].KeyChain)
+
+	restoreEnvs()
+}
+
+func TestValidateJSONSchemaWithGeneratedSchema(t *testing.T) {
+	type SCHEMAVAL struct {
+		DB struct {
+			HOST string `validate:"hostname"`
+			PORT int    `validate:"port"`
+		}
+		LEVEL string `validate:"oneof=debug info" default:"info"`
+	}
+
+	os.Clearenv()
+	setEnv("SCHEMAVAL_DB_HOST", "localhost")
+	setEnv("SCHEMAVAL_DB_PORT", "70000")
+
+	descriptions, err := Describe(SCHEMAVAL{}, "_", WithStrictMode())
+
+	assert.NoError(t, err)
+
+	tree, err := NewEnvTree("^SCHEMAVAL", "_")
+
+	assert.NoError(t, err)
+	assert.EqualError(t, tree.ValidateJSONSchema(NewJSONSchema(descriptions)), `1 error(s) occurred while validating schema :
+  - Value "70000" of variable "SCHEMAVAL_DB_PORT" is invalid : must be lower than or equal to 65535`)
+
+	restoreEnvs()
+}
+
+func TestValidateJSONSchemaWithGeneratedNonEmptyRule(t *testing.T) {
+	type SCHEMAVAL struct {
+		NAME string `validate:"nonempty,regex=^a*$"`
+	}
+
+	os.Clearenv()
+	setEnv("SCHEMAVAL_NAME", "")
+
+	descriptions, err := Describe(SCHEMAVAL{}, "_")
+
+	assert.NoError(t, err)
+
+	tree, err := NewEnvTree("^SCHEMAVAL", "_")
+
+	assert.NoError(t, err)
+	assert.EqualError(t, tree.ValidateJSONSchema(NewJSONSchema(descriptions)), `1 error(s) occurred while validating schema :
+  - Value "" of variable "SCHEMAVAL_NAME" is invalid : length must be greater than or equal to 1`)
+
+	restoreEnvs()
+}
+
+func TestParseJSONSchema(t *testing.T) {
+	schema, err := ParseJSONSchema(strings.NewReader(`{"type": "object", "additionalProperties": true, "properties": {"A": false}}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, &JSONSchema{}, schema.AdditionalProperties)
+	assert.Equal(t, &JSONSchema{Not: &JSONSchema{}}, schema.Properties["A"])
+
+	_, err = ParseJSONSchema(strings.NewReader(`{"type": 1}`))
+
+	assert.Error(t, err)
+
+	schema, err = ParseJSONSchema(strings.NewReader(`{"title": "config", "properties": {"A": {"type": ["string", "null"]}}}`))
+
+	assert.NoError(t, err)
+	assert.Equal(t, JSONSchemaType{"string", "null"}, schema.Properties["A"].Type)
+
+	for keyword, document := range map[string]string{
+		"$ref":             `{"$defs": {"x": {"type": "integer"}}, "properties": {"A": {"$ref": "#/$defs/x"}}}`,
+		"if":               `{"if": {"type": "integer"}}`,
+		"oneOf":            `{"properties": {"A": {"oneOf": [{"type": "integer"}]}}}`,
+		"const":            `{"additionalProperties": {"const": "a"}}`,
+		"exclusiveMinimum": `{"anyOf": [{"exclusiveMinimum": 1}]}`,
+		"items":            `{"not": {"items": true}}`,
+	} {
+		_, err = ParseJSONSchema(strings.NewReader(document))
+
+		assert.EqualError(t, err, fmt.Sprintf(`keyword "%s" is not supported`, keyword))
+	}
+}
+
+func TestMarshalJSONSchemaType(t *testing.T) {
+	data, err := json.Marshal(&JSONSchema{Type: JSONSchemaType{"string"}, AnyOf: []*JSONSchema{{Type: JSONSchemaType{"string", "null"}}, {}}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"type":"string","anyOf":[{"type":["string","null"]},{}]}`, string(data))
+}
+
+func TestInferValue(t *testing.T) {
+	type g struct {
+		raw      string
+		typ      JSONSchemaType
+		expected interface{}
+		ok       bool
+	}
+
+	tests := []g{
+		{"12", JSONSchemaType{"integer"}, 12, true},
+		{"1.5", JSONSchemaType{"integer"}, "1.5", false},
+		{"1.5", JSONSchemaType{"number"}, float32(1.5), true},
+		{"true", JSONSchemaType{"boolean"}, true, true},
+		{"12", JSONSchemaType{"string"}, "12", true},
+		{"12", JSONSchemaType{}, 12, true},
+		{"1.5", JSONSchemaType{}, float32(1.5), true},
+		{"false", JSONSchemaType{}, false, true},
+		{"test", JSONSchemaType{}, "test", true},
+		{"test", JSONSchemaType{"array"}, "test", false},
+		{"test", JSONSchemaType{"null"}, "test", false},
+		{"test", JSONSchemaType{"integer", "string"}, "test", true},
+		{"12", JSONSchemaType{"integer", "string"}, 12, true},
+		{"test", JSONSchemaType{"integer", "null"}, "test", false},
+	}
+
+	for _, test := range tests {
+		value, ok := inferValue(test.raw, test.typ)
+
+		assert.Equal(t, test.ok, ok)
+		assert.Equal(t, test.expected, value)
+	}
+}
@@ -0,0 +1,138 @@
+package envh
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Field describes a struct field filled by a Loader, it gathers
+// settings PopulateStruct reads from struct tags
+type Field struct {
+	// Keys are candidate keys matching the field, first one defined wins,
+	// last element of Path is used when there is none
+	Keys []string
+	// Path is go field path, DB.PORT for instance
+	Path        string
+	Default     string
+	HasDefault  bool
+	Secret      bool
+	Deprecation string
+	Deprecated  bool
+	// Rules are rules defined in a validate tag
+	Rules string
+}
+
+func (f Field) tag() fieldTag {
+	keys := f.Keys
+
+	if len(keys) == 0 {
+		path := strings.Split(f.Path, ".")
+		keys = []string{path[len(path)-1]}
+	}
+
+	return fieldTag{keys[0], keys, f.Secret, f.Default, f.HasDefault, f.Deprecation, f.Deprecated}
+}
+
+// Loader fills struct fields one by one without reflection,
+// it's used by loaders generated with envhgen command and
+// behaves like PopulateStruct : defaults, candidate keys, deprecations,
+// validate rules and hooks are handled the same way and every
+// error encountered is gathered in a PopulateError
+type Loader struct {
+	tree            *EnvTree
+	forceDefinition bool
+	errs            []FieldError
+	reports         deprecationReports
+}
+
+// NewLoader creates a loader reading variables from tree,
+// a missing variable is an error when strict is true
+func NewLoader(tree EnvTree, strict bool) *Loader {
+	return &Loader{&tree, strict, []FieldError{}, deprecationReports{}}
+}
+
+// Struct returns key chain of a nested struct field living below chain
+func (l *Loader) Struct(chain []string, f Field) []string {
+	tag := f.tag()
+	key := tag.resolveKey(l.tree, chain)
+
+	reportDeprecatedField(l.tree, chain, key, tag, l.reports)
+
+	return append(append([]string{}, chain...), key)
+}
+
+// Int returns value of an int field living below chain
+func (l *Loader) Int(chain []string, f Field) int {
+	return loadValue(l, chain, f, getInt)
+}
+
+// Float returns value of a float32 field living below chain
+func (l *Loader) Float(chain []string, f Field) float32 {
+	return loadValue(l, chain, f, getFloat)
+}
+
+// String returns value of a string field living below chain
+func (l *Loader) String(chain []string, f Field) string {
+	return loadValue(l, chain, f, getString)
+}
+
+// Bool returns value of a bool field living below chain
+func (l *Loader) Bool(chain []string, f Field) bool {
+	return loadValue(l, chain, f, getBool)
+}
+
+// Hook calls a function once every field of struct matching chain is set,
+// with sub tree of the struct, it's skipped if a field of the struct failed
+func (l *Loader) Hook(chain []string, path string, hook func(tree *EnvTree) error) {
+	if hasFieldError(l.errs, chain) {
+		return
+	}
+
+	subTree := findSubTreeOrEmpty(l.tree, chain)
+
+	if err := hook(&subTree); err != nil {
+		l.errs = append(l.errs, FieldError{chain, path, err})
+	}
+}
+
+// Err returns a PopulateError gathering every error encountered, nil otherwise
+func (l *Loader) Err() error {
+	if len(l.errs) > 0 {
+		return PopulateError{l.errs, []UnknownKeyError{}}
+	}
+
+	return nil
+}
+
+func loadValue[T any](l *Loader, chain []string, f Field, get func(fun func() (string, bool), ref varRef) (T, error)) T {
+	tag := f.tag()
+	key := tag.resolveKey(l.tree, chain)
+	keyChain := append(append([]string{}, chain...), key)
+	path := strings.Split(f.Path, ".")
+
+	reportDeprecatedField(l.tree, chain, key, tag, l.reports)
+
+	v, err := get(tag.lookup(l.tree, keyChain, l.reports), l.tree.ref(keyChain))
+
+	if _, ok := err.(WrongTypeError); err != nil && (l.forceDefinition || ok) {
+		l.errs = append(l.errs, newFieldError(keyChain, path, tag, err))
+
+		return v
+	}
+
+	rules, err := parseValidationTag(f.Rules)
+
+	if err == nil && (len(rules) == 0 || !tag.isDefined(l.tree, keyChain)) {
+		return v
+	}
+
+	if err == nil {
+		err = validateValue(reflect.ValueOf(v), l.tree.ref(keyChain), rules)
+	}
+
+	if err != nil {
+		l.errs = append(l.errs, newFieldError(keyChain, path, tag, err))
+	}
+
+	return v
+}
@@ -0,0 +1,108 @@
+package envh
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoader(t *testing.T) {
+	os.Clearenv()
+	setEnv("LOADER_DB_HOST", "localhost")
+	setEnv("LOADER_DB_PORT", "70000")
+	setEnv("LOADER_RATIO", "0.5")
+	setEnv("LOADER_ENABLED", "true")
+
+	tree, err := NewEnvTree("^LOADER", "_")
+
+	assert.NoError(t, err)
+
+	type g struct {
+		strict bool
+		err    string
+	}
+
+	tests := []g{
+		{
+			false,
+			`2 error(s) occurred while populating struct :
+  - Field "DB.PORT" : Value "70000" of variable "LOADER_DB_PORT" is invalid : must be a valid port comprised between 1 and 65535
+  - Field "MAILER" : mailer is not configured`,
+		},
+		{
+			true,
+			`3 error(s) occurred while populating struct :
+  - Field "DB.PORT" : Value "70000" of variable "LOADER_DB_PORT" is invalid : must be a valid port comprised between 1 and 65535
+  - Field "NAME" : Variable "LOADER_NAME" not found
+  - Field "MAILER" : mailer is not configured`,
+		},
+	}
+
+	for _, test := range tests {
+		l := NewLoader(tree, test.strict)
+		chain := []string{"LOADER"}
+		dbChain := l.Struct(chain, Field{Keys: []string{"DATABASE", "DB"}, Path: "DB"})
+		hooks := []string{}
+
+		assert.Equal(t, "localhost", l.String(dbChain, Field{Keys: []string{"HOST"}, Path: "DB.HOST"}))
+		assert.Equal(t, 70000, l.Int(dbChain, Field{Keys: []string{"PORT"}, Path: "DB.PORT", Rules: "port"}))
+		assert.Equal(t, float32(0.5), l.Float(chain, Field{Keys: []string{"RATIO"}, Path: "RATIO"}))
+		assert.True(t, l.Bool(chain, Field{Keys: []string{"ENABLED"}, Path: "ENABLED"}))
+		assert.Equal(t, "app", l.String(chain, Field{Keys: []string{"TITLE"}, Path: "TITLE", Default: "app", HasDefault: true}))
+		assert.Equal(t, "", l.String(chain, Field{Keys: []string{"NAME"}, Path: "NAME"}))
+
+		l.Hook(dbChain, "DB", func(tree *EnvTree) error {
+			hooks = append(hooks, "DB")
+
+			return nil
+		})
+
+		l.Hook([]string{"LOADER", "MAILER"}, "MAILER", func(tree *EnvTree) error {
+			hooks = append(hooks, tree.GetKey())
+
+			return errors.New("mailer is not configured")
+		})
+
+		assert.EqualError(t, l.Err(), test.err)
+		assert.Equal(t, []string{""}, hooks)
+	}
+
+	restoreEnvs()
+}
+
+func TestLoaderWithoutError(t *testing.T) {
+	os.Clearenv()
+	setEnv("LOADER_TOKEN", "secret")
+
+	tree, err := NewEnvTree("^LOADER", "_")
+
+	assert.NoError(t, err)
+
+	l := NewLoader(tree, true)
+
+	assert.Equal(t, "secret", l.String([]string{"LOADER"}, Field{Keys: []string{"TOKEN"}, Path: "TOKEN", Secret: true, Rules: "nonempty"}))
+	assert.NoError(t, l.Err())
+
+	restoreEnvs()
+}
+
+func TestLoaderWithoutKeys(t *testing.T) {
+	os.Clearenv()
+	setEnv("LOADER_DB_PORT", "5432")
+
+	tree, err := NewEnvTree("^LOADER", "_")
+
+	assert.NoError(t, err)
+
+	l := NewLoader(tree, true)
+
+	assert.Equal(t, 5432, l.Int([]string{"LOADER", "DB"}, Field{Path: "DB.PORT"}))
+	assert.NoError(t, l.Err())
+	assert.NotPanics(t, func() {
+		l.String([]string{"LOADER"}, Field{})
+	})
+
+	restoreEnvs()
+}
@@ -0,0 +1,165 @@
+package envh
+
+import (
+	"reflect"
+	"strings"
+)
+
+// UnknownKeyPolicy defines what to do with variables defined below
+// a struct key which don't match any field
+type UnknownKeyPolicy int
+
+const (
+	// IgnoreUnknownKeys silently ignores unknown variables
+	IgnoreUnknownKeys UnknownKeyPolicy = iota
+	// ReportUnknownKeys reports unknown variables as errors
+	ReportUnknownKeys
+)
+
+// NamingStrategy turns a struct field name into the key matching
+// the field in tree, it's not applied to keys defined in a tag
+type NamingStrategy func(fieldName string) string
+
+// IdentityNaming uses field name as key, it's the default strategy
+func IdentityNaming(fieldName string) string {
+	return fieldName
+}
+
+// UpperCaseNaming uses upper cased field name as key
+func UpperCaseNaming(fieldName string) string {
+	return strings.ToUpper(fieldName)
+}
+
+// PopulateOption configures the way Populate fills a structure
+type PopulateOption func(*populateOptions)
+
+type populateOptions struct {
+	forceDefinition bool
+	tagName         string
+	naming          NamingStrategy
+	rootKeyChain    []string
+	hasRootKeyChain bool
+	decoders        map[reflect.Type]func(value string) (interface{}, error)
+	unknownKeys     UnknownKeyPolicy
+	walkers         []StructWalker
+	hooks           []func(tree *EnvTree, structure interface{}) error
+	variants        map[reflect.Type]variantSet
+	provenance      Provenance
+	flat            bool
+	// reports holds deprecated variables reported during a population
+	reports deprecationReports
+}
+
+func newPopulateOptions(options ...PopulateOption) *populateOptions {
+	opts := &populateOptions{
+		tagName:  tagName,
+		naming:   IdentityNaming,
+		decoders: map[reflect.Type]func(value string) (interface{}, error){},
+		variants: map[reflect.Type]variantSet{},
+	}
+
+	for _, option := range options {
+		option(opts)
+	}
+
+	return opts
+}
+
+// newFlatPopulateOptions creates options used when variables don't follow
+// a hierarchical scheme, every field matches a full variable name
+func newFlatPopulateOptions(options ...PopulateOption) *populateOptions {
+	opts := newPopulateOptions(append([]PopulateOption{WithTagName(envTagName)}, options...)...)
+	opts.flat = true
+	opts.rootKeyChain = []string{}
+	opts.hasRootKeyChain = true
+	opts.unknownKeys = IgnoreUnknownKeys
+
+	return opts
+}
+
+// WithStrictMode reports a missing variable as an error,
+// missing values are ignored otherwise
+func WithStrictMode() PopulateOption {
+	return func(opts *populateOptions) {
+		opts.forceDefinition = true
+	}
+}
+
+// WithTagName reads field settings from a custom tag instead of envh tag
+func WithTagName(name string) PopulateOption {
+	return func(opts *populateOptions) {
+		opts.tagName = name
+	}
+}
+
+// WithNamingStrategy defines how a field name is turned into a key
+func WithNamingStrategy(strategy NamingStrategy) PopulateOption {
+	return func(opts *populateOptions) {
+		opts.naming = strategy
+	}
+}
+
+// WithRootKey populates structure from node matching key chain instead of using
+// struct type name as first key, an empty key chain means current tree root is the struct itself
+func WithRootKey(keyChain ...string) PopulateOption {
+	return func(opts *populateOptions) {
+		opts.rootKeyChain = keyChain
+		opts.hasRootKeyChain = true
+	}
+}
+
+// WithDecoder registers a function used to convert a variable to a field of type T,
+// it takes precedence over regular conversions, so it's possible to support a type
+// which is not supported natively or to override how a supported one is converted
+func WithDecoder[T any](decode func(value string) (T, error)) PopulateOption {
+	return func(opts *populateOptions) {
+		opts.decoders[reflect.TypeOf((*T)(nil)).Elem()] = func(value string) (interface{}, error) {
+			return decode(value)
+		}
+	}
+}
+
+// WithUnknownKeyPolicy defines what to do with variables defined below
+// struct key which don't match any field
+func WithUnknownKeyPolicy(policy UnknownKeyPolicy) PopulateOption {
+	return func(opts *populateOptions) {
+		opts.unknownKeys = policy
+	}
+}
+
+// WithWalker registers a StructWalker called for every field before the one
+// the structure may implement, it's useful to control how a struct is populated
+// without having to add a method to it
+func WithWalker(walker StructWalker) PopulateOption {
+	return func(opts *populateOptions) {
+		opts.walkers = append(opts.walkers, walker)
+	}
+}
+
+// WithHook registers a function called once structure is fully populated,
+// after StructFinalizer and StructValidator, tree given is the sub tree matching structure.
+// Hook is not called if an error occurred before
+func WithHook(hook func(tree *EnvTree, structure interface{}) error) PopulateOption {
+	return func(opts *populateOptions) {
+		opts.hooks = append(opts.hooks, hook)
+	}
+}
+
+// rootKey returns key chain leading to the node matching a struct type
+func (opts *populateOptions) rootKey(typ reflect.Type) []string {
+	if opts.hasRootKeyChain {
+		return append([]string{}, opts.rootKeyChain...)
+	}
+
+	return []string{typ.Name()}
+}
+
+// structKeyChain returns key chain of a nested struct, when variables
+// are not hierarchical, nested structs share key chain of their parent
+func (opts *populateOptions) structKeyChain(keyChain []string) []string {
+	if opts.flat {
+		return keyChain[:len(keyChain)-1]
+	}
+
+	return keyChain
+}
@@ -0,0 +1,310 @@
+package envh
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type OPTIONS struct {
+	Host string
+	Port int
+	DB   struct {
+		Name string `conf:"DATABASE"`
+	}
+}
+
+type OPTIONSDECODER struct {
+	Host    string
+	Timeout time.Duration
+}
+
+type optionWalker struct {
+	keyChains []string
+}
+
+func (o *optionWalker) Walk(tree *EnvTree, keyChain []string) (bool, error) {
+	o.keyChains = append(o.keyChains, strings.Join(keyChain, "_"))
+
+	return strings.Join(keyChain, "_") == "OPTIONS_Host", nil
+}
+
+func TestPopulateWithoutOptions(t *testing.T) {
+	setEnv("OPTIONS_Host", "localhost")
+	setEnv("OPTIONS_Port", "3306")
+
+	actual := OPTIONS{}
+
+	tree, err := NewEnvTree("^OPTIONS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual)
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", actual.Host)
+	assert.Equal(t, 3306, actual.Port)
+}
+
+func TestPopulateWithStrictMode(t *testing.T) {
+	setEnv("OPTIONS_Host", "localhost")
+
+	actual := OPTIONS{}
+
+	tree, err := NewEnvTree("^OPTIONS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithStrictMode())
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `2 error(s) occurred while populating struct :
+  - Field "Port" : Variable "OPTIONS_Port" not found
+  - Field "DB.Name" : Variable "OPTIONS_DB_Name" not found`)
+	assert.True(t, errors.Is(err, ErrVariableNotFound))
+}
+
+func TestPopulateWithTagNameAndNamingStrategy(t *testing.T) {
+	setEnv("OPTIONS_HOST", "localhost")
+	setEnv("OPTIONS_PORT", "3306")
+	setEnv("OPTIONS_DB_DATABASE", "app")
+
+	actual := OPTIONS{}
+
+	tree, err := NewEnvTree("^OPTIONS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithTagName("conf"), WithNamingStrategy(UpperCaseNaming))
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", actual.Host)
+	assert.Equal(t, 3306, actual.Port)
+	assert.Equal(t, "app", actual.DB.Name)
+}
+
+func TestPopulateWithRootKey(t *testing.T) {
+	setEnv("OPTIONS_SERVER_HOST", "localhost")
+	setEnv("OPTIONS_SERVER_PORT", "3306")
+
+	actual := OPTIONS{}
+
+	tree, err := NewEnvTree("^OPTIONS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithRootKey("OPTIONS", "SERVER"), WithNamingStrategy(UpperCaseNaming))
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost", actual.Host)
+	assert.Equal(t, 3306, actual.Port)
+}
+
+func TestPopulateWithDecoder(t *testing.T) {
+	type test struct {
+		env      string
+		setup    func(actual *OPTIONSDECODER, err error)
+		decoders []PopulateOption
+	}
+
+	tests := []test{
+		{
+			"1m30s",
+			func(actual *OPTIONSDECODER, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, 90*time.Second, actual.Timeout)
+			},
+			[]PopulateOption{WithDecoder(time.ParseDuration)},
+		},
+		{
+			"whatever",
+			func(actual *OPTIONSDECODER, err error) {
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "Timeout" : Value "whatever" of variable "OPTIONSDECODER_Timeout" can't be converted to type "time.Duration"`)
+				assert.True(t, errors.Is(err, ErrWrongType))
+			},
+			[]PopulateOption{WithDecoder(time.ParseDuration)},
+		},
+		{
+			"1m30s",
+			func(actual *OPTIONSDECODER, err error) {
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "Timeout" : Type "int64" of variable "OPTIONSDECODER_Timeout" is not supported : you must provide "int32, float32, string, boolean or struct"`)
+			},
+			[]PopulateOption{},
+		},
+		{
+			"1m30s",
+			func(actual *OPTIONSDECODER, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "LOCALHOST", actual.Host)
+			},
+			[]PopulateOption{WithDecoder(time.ParseDuration), WithDecoder(func(value string) (string, error) {
+				return strings.ToUpper(value), nil
+			})},
+		},
+	}
+
+	for _, test := range tests {
+		setEnv("OPTIONSDECODER_Host", "localhost")
+		setEnv("OPTIONSDECODER_Timeout", test.env)
+
+		actual := OPTIONSDECODER{}
+
+		tree, err := NewEnvTree("^OPTIONSDECODER", "_")
+
+		assert.NoError(t, err)
+
+		err = tree.Populate(&actual, test.decoders...)
+
+		restoreEnvs()
+
+		test.setup(&actual, err)
+	}
+}
+
+func TestPopulateWithDecoderReturningNil(t *testing.T) {
+	type OPTIONSNILDECODER struct {
+		Name fmt.Stringer
+	}
+
+	setEnv("OPTIONSNILDECODER_Name", "whatever")
+
+	actual := OPTIONSNILDECODER{}
+
+	tree, err := NewEnvTree("^OPTIONSNILDECODER", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithDecoder(func(value string) (fmt.Stringer, error) {
+		return nil, nil
+	}))
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Nil(t, actual.Name)
+}
+
+func TestPopulateWithUnknownKeyPolicy(t *testing.T) {
+	setEnv("OPTIONS_Hots", "localhost")
+
+	actual := OPTIONS{}
+
+	tree, err := NewEnvTree("^OPTIONS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithUnknownKeyPolicy(ReportUnknownKeys))
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Variable "OPTIONS_Hots" doesn't match any field, did you mean "OPTIONS_Host" ?`)
+}
+
+func TestPopulateWithWalker(t *testing.T) {
+	setEnv("OPTIONS_Host", "localhost")
+	setEnv("OPTIONS_Port", "3306")
+
+	actual := OPTIONS{}
+	walker := optionWalker{}
+
+	tree, err := NewEnvTree("^OPTIONS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithWalker(&walker))
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "", actual.Host, "Host must be bypassed by walker")
+	assert.Equal(t, 3306, actual.Port)
+	assert.Equal(t, []string{"OPTIONS_Host", "OPTIONS_Port", "OPTIONS_DB", "OPTIONS_DB_Name"}, walker.keyChains)
+}
+
+func TestPopulateWithHook(t *testing.T) {
+	type test struct {
+		env   string
+		setup func(calls []string, err error)
+	}
+
+	tests := []test{
+		{
+			"3306",
+			func(calls []string, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, []string{"first localhost", "second"}, calls)
+			},
+		},
+		{
+			"whatever",
+			func(calls []string, err error) {
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "Port" : Value "whatever" of variable "OPTIONS_Port" can't be converted to type "int"`)
+				assert.Len(t, calls, 0, "Hooks must not be called when an error occurred")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		setEnv("OPTIONS_Host", "localhost")
+		setEnv("OPTIONS_Port", test.env)
+
+		actual := OPTIONS{}
+		calls := []string{}
+
+		tree, err := NewEnvTree("^OPTIONS", "_")
+
+		assert.NoError(t, err)
+
+		err = tree.Populate(&actual,
+			WithHook(func(tree *EnvTree, structure interface{}) error {
+				host, err := tree.FindString("Host")
+				calls = append(calls, "first "+host)
+
+				return err
+			}),
+			WithHook(func(tree *EnvTree, structure interface{}) error {
+				calls = append(calls, "second")
+
+				return nil
+			}),
+		)
+
+		restoreEnvs()
+
+		test.setup(calls, err)
+	}
+}
+
+func TestPopulateWithFailingHook(t *testing.T) {
+	setEnv("OPTIONS_Host", "localhost")
+
+	actual := OPTIONS{}
+
+	tree, err := NewEnvTree("^OPTIONS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithHook(func(tree *EnvTree, structure interface{}) error {
+		return errors.New("host is not reachable")
+	}))
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Struct "OPTIONS" : host is not reachable`)
+}
@@ -0,0 +1,59 @@
+package envh
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Source describes where value of a field comes from
+type Source struct {
+	// Variable is name of the variable value was read from,
+	// it's empty when a default value is used
+	Variable string
+	// KeyChain is key chain of the variable in tree
+	KeyChain []string
+	// Default is true when value comes from a default tag
+	Default bool
+	// Secret is true if the field is marked as secret or if variable name
+	// is sensitive, a report must not print value of such a field
+	Secret bool
+}
+
+// Provenance gives source of every populated field indexed by field path, DB.URL for instance
+type Provenance map[string]Source
+
+// WithProvenance fills provenance with source of every field populated
+// from a variable or from a default value, it's useful to know which key
+// won when several candidates are declared for a field
+func WithProvenance(provenance Provenance) PopulateOption {
+	return func(opts *populateOptions) {
+		opts.provenance = provenance
+	}
+}
+
+// recordSource stores source of a populated field holding a single value
+func recordSource(tree *EnvTree, val reflect.Value, keyChain []string, path []string, tag fieldTag, opts *populateOptions) {
+	if opts.provenance == nil {
+		return
+	}
+
+	name := tree.ref(keyChain).name
+	secret := isSecret(name, tag.secret || isSecretType(val.Type()))
+
+	if _, ok := opts.decoder(val.Type()); !ok {
+		if inner, ok := unwrapSecret(val); ok {
+			val = inner
+		}
+
+		if val.Kind() == reflect.Struct {
+			return
+		}
+	}
+
+	switch {
+	case tree.HasSubTreeValueUnsecured(keyChain...):
+		opts.provenance[strings.Join(path, ".")] = Source{name, keyChain, false, secret}
+	case tag.hasDefault:
+		opts.provenance[strings.Join(path, ".")] = Source{"", []string{}, true, secret}
+	}
+}
@@ -0,0 +1,134 @@
+package envh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ALIASES struct {
+	DB struct {
+		URL  string `envh:"URL|DSN" validate:"url"`
+		PORT int    `envh:"PORT|NUMBER" default:"5432"`
+	} `envh:"DATABASE|DB"`
+	NAME string
+}
+
+func TestPopulateWithAliases(t *testing.T) {
+	type test struct {
+		envs     map[string]string
+		expected string
+		source   Source
+	}
+
+	tests := []test{
+		{
+			map[string]string{"ALIASES_DATABASE_URL": "postgres://new", "ALIASES_DB_DSN": "postgres://old"},
+			"postgres://new",
+			Source{"ALIASES_DATABASE_URL", []string{"ALIASES", "DATABASE", "URL"}, false, false},
+		},
+		{
+			map[string]string{"ALIASES_DATABASE_DSN": "postgres://dsn"},
+			"postgres://dsn",
+			Source{"ALIASES_DATABASE_DSN", []string{"ALIASES", "DATABASE", "DSN"}, false, false},
+		},
+		{
+			map[string]string{"ALIASES_DB_DSN": "postgres://old"},
+			"postgres://old",
+			Source{"ALIASES_DB_DSN", []string{"ALIASES", "DB", "DSN"}, false, false},
+		},
+	}
+
+	for _, test := range tests {
+		for k, v := range test.envs {
+			setEnv(k, v)
+		}
+
+		actual := ALIASES{}
+		provenance := Provenance{}
+
+		tree, err := NewEnvTree("^ALIASES", "_")
+
+		assert.NoError(t, err)
+
+		err = tree.Populate(&actual, WithProvenance(provenance), WithUnknownKeyPolicy(ReportUnknownKeys))
+
+		restoreEnvs()
+
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, actual.DB.URL)
+		assert.Equal(t, 5432, actual.DB.PORT)
+		assert.Equal(t, Provenance{"DB.URL": test.source, "DB.PORT": {"", []string{}, true, false}}, provenance)
+	}
+}
+
+func TestPopulateWithAliasesAndErrors(t *testing.T) {
+	setEnv("ALIASES_DB_DSN", "localhost")
+	setEnv("ALIASES_DB_NUMBER", "whatever")
+
+	actual := ALIASES{}
+
+	tree, err := NewEnvTree("^ALIASES", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithStrictMode())
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `3 error(s) occurred while populating struct :
+  - Field "NAME" : Variable "ALIASES_NAME" not found
+  - Field "DB.URL" : Value "localhost" of variable "ALIASES_DB_DSN" is invalid : must be a valid URL
+  - Field "DB.PORT" : Value "whatever" of variable "ALIASES_DB_NUMBER" can't be converted to type "int"`)
+}
+
+func TestEnvPopulateWithAliases(t *testing.T) {
+	type CONFIG struct {
+		URL string `env:"TEST99_DATABASE_URL|TEST99_DB_URL|TEST99_PG_URL"`
+	}
+
+	setEnv("TEST99_DB_URL", "postgres://db")
+	setEnv("TEST99_PG_URL", "postgres://pg")
+
+	actual := CONFIG{}
+	provenance := Provenance{}
+
+	err := NewEnv().Populate(&actual, WithProvenance(provenance))
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "postgres://db", actual.URL)
+	assert.Equal(t, "TEST99_DB_URL", provenance["URL"].Variable)
+}
+
+func TestPopulateWithProvenanceOfSecrets(t *testing.T) {
+	type TEST99 struct {
+		DSN      string         `envh:",secret"`
+		PASSWORD string         `default:"hunter2"`
+		API      Secret[string] `default:"key"`
+		HOST     string
+	}
+
+	setEnv("TEST99_DSN", "postgres://db")
+	setEnv("TEST99_HOST", "localhost")
+
+	actual := TEST99{}
+	provenance := Provenance{}
+
+	tree, err := NewEnvTree("^TEST99", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithProvenance(provenance))
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, Provenance{
+		"DSN":      {"TEST99_DSN", []string{"TEST99", "DSN"}, false, true},
+		"PASSWORD": {"", []string{}, true, true},
+		"API":      {"", []string{}, true, true},
+		"HOST":     {"TEST99_HOST", []string{"TEST99", "HOST"}, false, false},
+	}, provenance)
+}
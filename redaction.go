@@ -0,0 +1,84 @@
+package envh
+
+import (
+	"regexp"
+	"sync"
+)
+
+// RedactionMask replaces sensitive values in errors and in every output produced
+const RedactionMask = "******"
+
+// DefaultSensitiveKeyPattern is the pattern used to detect variables
+// holding sensitive values from their name, unambiguous words match anywhere
+// so PGPASSWORD or SECRETKEY are sensitive, while KEY, PASS and PWD must be
+// whole segments of a name so MONKEY or KEYBOARD_LAYOUT aren't
+const DefaultSensitiveKeyPattern = `(?i)(PASSWORD|PASSWD|PASSPHRASE|SECRET|TOKEN|CREDENTIAL|PRIVATE)|(^|[^A-Z0-9])(PASS|PWD|KEYS?)([^A-Z0-9]|$)`
+
+var sensitiveKeyRegexp = struct {
+	sync.RWMutex
+	reg *regexp.Regexp
+}{reg: regexp.MustCompile(DefaultSensitiveKeyPattern)}
+
+// SetSensitiveKeyPattern overrides the pattern used to detect variables holding
+// sensitive values from their name, values of matching variables are redacted
+// everywhere they could leak. An empty pattern disables detection,
+// fields marked with envh:",secret" tag are still redacted
+func SetSensitiveKeyPattern(reg string) error {
+	var r *regexp.Regexp
+
+	if reg != "" {
+		var err error
+
+		if r, err = regexp.Compile(reg); err != nil {
+			return err
+		}
+	}
+
+	sensitiveKeyRegexp.Lock()
+	defer sensitiveKeyRegexp.Unlock()
+
+	sensitiveKeyRegexp.reg = r
+
+	return nil
+}
+
+// IsSensitiveKey returns true if a variable name matches
+// sensitive key pattern
+func IsSensitiveKey(variable string) bool {
+	sensitiveKeyRegexp.RLock()
+	defer sensitiveKeyRegexp.RUnlock()
+
+	return sensitiveKeyRegexp.reg != nil && sensitiveKeyRegexp.reg.MatchString(variable)
+}
+
+// isSecret returns true if variable is marked
+// as secret or if its name is sensitive
+func isSecret(variable string, secret bool) bool {
+	return secret || IsSensitiveKey(variable)
+}
+
+// redact returns mask in place of value if variable is
+// marked as secret or if its name is sensitive
+func redact(value interface{}, variable string, secret bool) interface{} {
+	if isSecret(variable, secret) {
+		return RedactionMask
+	}
+
+	return value
+}
+
+// markSecret flags errors carrying a value as secret
+func markSecret(err error) error {
+	switch e := err.(type) {
+	case WrongTypeError:
+		e.Secret = true
+
+		return e
+	case ValidationError:
+		e.Secret = true
+
+		return e
+	}
+
+	return err
+}
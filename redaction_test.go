@@ -0,0 +1,53 @@
+package envh
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsSensitiveKey(t *testing.T) {
+	for _, key := range []string{"DB_PASSWORD", "GITHUB_TOKEN", "APP_SECRET", "AWS_SECRET_ACCESS_KEY", "api_key", "PWD", "SSH_PRIVATE_KEY", "APP.TOKEN", "PGPASSWORD", "APP_DBPASSWORD", "SECRETKEY", "API_KEYS"} {
+		assert.True(t, IsSensitiveKey(key), key)
+	}
+
+	for _, key := range []string{"DB_HOST", "PORT", "", "MONKEY", "KEYBOARD_LAYOUT", "PASSENGER_COUNT", "PWDUMP"} {
+		assert.False(t, IsSensitiveKey(key), key)
+	}
+}
+
+func TestSetSensitiveKeyPattern(t *testing.T) {
+	defer func() {
+		assert.NoError(t, SetSensitiveKeyPattern(DefaultSensitiveKeyPattern))
+	}()
+
+	assert.EqualError(t, SetSensitiveKeyPattern("?"), "error parsing regexp: missing argument to repetition operator: `?`")
+	assert.True(t, IsSensitiveKey("DB_PASSWORD"), "Must keep previous pattern when an error occurred")
+
+	assert.NoError(t, SetSensitiveKeyPattern("^DSN$"))
+	assert.True(t, IsSensitiveKey("DSN"))
+	assert.False(t, IsSensitiveKey("DB_PASSWORD"))
+
+	assert.NoError(t, SetSensitiveKeyPattern(""))
+	assert.False(t, IsSensitiveKey("DSN"))
+}
+
+func TestErrorsAreRedacted(t *testing.T) {
+	type g struct {
+		err      error
+		expected string
+	}
+
+	tests := []g{
+		{WrongTypeError{Value: "hunter2", Type: "int", Variable: "DB_PASSWORD"}, `Value "******" of variable "DB_PASSWORD" can't be converted to type "int"`},
+		{WrongTypeError{Value: "hunter2", Type: "int", Variable: "PGPASSWORD"}, `Value "******" of variable "PGPASSWORD" can't be converted to type "int"`},
+		{WrongTypeError{Value: "hunter2", Type: "int", Variable: "DB_DSN", Secret: true}, `Value "******" of variable "DB_DSN" can't be converted to type "int"`},
+		{WrongTypeError{Value: "hunter2", Type: "int", Variable: "DB_DSN"}, `Value "hunter2" of variable "DB_DSN" can't be converted to type "int"`},
+		{ValidationError{Value: "hunter2", Message: "must not be empty", Variable: "APP_TOKEN"}, `Value "******" of variable "APP_TOKEN" is invalid : must not be empty`},
+		{ValidationError{Value: "hunter2", Message: "must not be empty", Variable: "APP_DSN", Secret: true}, `Value "******" of variable "APP_DSN" is invalid : must not be empty`},
+	}
+
+	for _, test := range tests {
+		assert.EqualError(t, test.err, test.expected)
+	}
+}
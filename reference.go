@@ -0,0 +1,131 @@
+package envh
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteEnvExample writes a commented .env.example file listing described variables,
+// a variable with a default value is set with it, values of secret variables
+// and of variables whose name is sensitive are always left empty
+func WriteEnvExample(w io.Writer, descriptions []VariableDescription) error {
+	for i, d := range descriptions {
+		lines := []string{}
+
+		if i > 0 {
+			lines = append(lines, "")
+		}
+
+		if d.Description != "" {
+			lines = append(lines, "# "+d.Description)
+		}
+
+		value := ""
+
+		if d.HasDefault && !isSecret(d.Name, d.Secret) {
+			value = d.Default
+		}
+
+		lines = append(lines, "# "+strings.Join(variableAttributes(d), ", "), fmt.Sprintf("%s=%s", d.Name, value))
+
+		if _, err := fmt.Fprintln(w, strings.Join(lines, "\n")); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteMarkdown writes a Markdown reference table listing described variables
+// with their type, their default value, if they're required and their description,
+// default values of secret variables are redacted
+func WriteMarkdown(w io.Writer, descriptions []VariableDescription) error {
+	lines := []string{
+		"| Variable | Type | Required | Default | Description |",
+		"| --- | --- | --- | --- | --- |",
+	}
+
+	for _, d := range descriptions {
+		required := "no"
+
+		if d.Required {
+			required = "yes"
+		}
+
+		value := ""
+
+		if d.HasDefault {
+			value = fmt.Sprintf("`%v`", redact(d.Default, d.Name, d.Secret))
+		}
+
+		description := []string{}
+
+		if d.Description != "" {
+			description = append(description, d.Description)
+		}
+
+		if d.Deprecated {
+			description = append(description, "Deprecated.")
+		}
+
+		if d.Variant != "" {
+			description = append(description, fmt.Sprintf("Only with `%s` variant.", d.Variant))
+		}
+
+		if len(d.Aliases) > 0 {
+			description = append(description, fmt.Sprintf("Aliases : `%s`.", strings.Join(d.Aliases, "`, `")))
+		}
+
+		if d.Rules != "" {
+			description = append(description, fmt.Sprintf("Rules : `%s`.", d.Rules))
+		}
+
+		lines = append(lines, fmt.Sprintf("| `%s` | `%s` | %s | %s | %s |", d.Name, d.Type, required, value, escapeMarkdownCell(strings.Join(description, " "))))
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+
+	return err
+}
+
+// variableAttributes returns a short description of every variable attribute
+func variableAttributes(d VariableDescription) []string {
+	attributes := []string{"type: " + d.Type}
+
+	if d.Required {
+		attributes = append(attributes, "required")
+	} else {
+		attributes = append(attributes, "optional")
+	}
+
+	if d.HasDefault {
+		attributes = append(attributes, fmt.Sprintf("default: %v", redact(d.Default, d.Name, d.Secret)))
+	}
+
+	if d.Secret {
+		attributes = append(attributes, "secret")
+	}
+
+	if d.Deprecated {
+		attributes = append(attributes, "deprecated")
+	}
+
+	if d.Variant != "" {
+		attributes = append(attributes, fmt.Sprintf(`variant: %s`, d.Variant))
+	}
+
+	if len(d.Aliases) > 0 {
+		attributes = append(attributes, "aliases: "+strings.Join(d.Aliases, " "))
+	}
+
+	if d.Rules != "" {
+		attributes = append(attributes, "rules: "+d.Rules)
+	}
+
+	return attributes
+}
+
+func escapeMarkdownCell(s string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(s)
+}
@@ -0,0 +1,57 @@
+package envh
+
+import (
+	"os"
+)
+
+func ExampleWriteEnvExample() {
+	type SERVER struct {
+		HOST     string `description:"listen host"`
+		PORT     int    `default:"8080" description:"listen port"`
+		PASSWORD string `description:"admin password"`
+	}
+
+	descriptions, err := Describe(SERVER{}, "_", WithStrictMode())
+
+	if err != nil {
+		return
+	}
+
+	if err = WriteEnvExample(os.Stdout, descriptions); err != nil {
+		return
+	}
+	// Output:
+	// # listen host
+	// # type: string, required
+	// SERVER_HOST=
+	//
+	// # listen port
+	// # type: int, optional, default: 8080
+	// SERVER_PORT=8080
+	//
+	// # admin password
+	// # type: string, required, secret
+	// SERVER_PASSWORD=
+}
+
+func ExampleWriteMarkdown() {
+	type SERVER struct {
+		HOST string `description:"listen host"`
+		PORT int    `default:"8080" description:"listen port"`
+	}
+
+	descriptions, err := Describe(SERVER{}, "_", WithStrictMode())
+
+	if err != nil {
+		return
+	}
+
+	if err = WriteMarkdown(os.Stdout, descriptions); err != nil {
+		return
+	}
+	// Output:
+	// | Variable | Type | Required | Default | Description |
+	// | --- | --- | --- | --- | --- |
+	// | `SERVER_HOST` | `string` | yes |  | listen host |
+	// | `SERVER_PORT` | `int` | no | `8080` | listen port |
+}
@@ -0,0 +1,69 @@
+package envh
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("failure")
+}
+
+func referenceDescriptions() []VariableDescription {
+	return []VariableDescription{
+		{Name: "APP_DB_URL", Type: "string", Required: true, Description: "database url", Aliases: []string{"APP_DB_DSN"}, Rules: "url"},
+		{Name: "APP_DB_PORT", Type: "int", Default: "5432", HasDefault: true, Description: "database port | number"},
+		{Name: "APP_DB_PASSWORD", Type: "string", Default: "hunter2", HasDefault: true, Secret: true},
+		{Name: "APP_STORAGE_BUCKET", Type: "string", Variant: "s3", Deprecated: true},
+		{Name: "APP_API_TOKEN", Type: "string", Default: "t0k3n", HasDefault: true},
+	}
+}
+
+func TestWriteEnvExample(t *testing.T) {
+	buf := bytes.Buffer{}
+
+	err := WriteEnvExample(&buf, referenceDescriptions())
+
+	assert.NoError(t, err)
+	assert.Equal(t, `# database url
+# type: string, required, aliases: APP_DB_DSN, rules: url
+APP_DB_URL=
+
+# database port | number
+# type: int, optional, default: 5432
+APP_DB_PORT=5432
+
+# type: string, optional, default: ******, secret
+APP_DB_PASSWORD=
+
+# type: string, optional, deprecated, variant: s3
+APP_STORAGE_BUCKET=
+
+# type: string, optional, default: ******
+APP_API_TOKEN=
+`, buf.String())
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.NotContains(t, buf.String(), "t0k3n")
+	assert.EqualError(t, WriteEnvExample(failingWriter{}, referenceDescriptions()), "failure")
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	buf := bytes.Buffer{}
+
+	err := WriteMarkdown(&buf, referenceDescriptions())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "| Variable | Type | Required | Default | Description |\n"+
+		"| --- | --- | --- | --- | --- |\n"+
+		"| `APP_DB_URL` | `string` | yes |  | database url Aliases : `APP_DB_DSN`. Rules : `url`. |\n"+
+		"| `APP_DB_PORT` | `int` | no | `5432` | database port \\| number |\n"+
+		"| `APP_DB_PASSWORD` | `string` | no | `******` |  |\n"+
+		"| `APP_STORAGE_BUCKET` | `string` | no |  | Deprecated. Only with `s3` variant. |\n"+
+		"| `APP_API_TOKEN` | `string` | no | `******` |  |\n", buf.String())
+	assert.EqualError(t, WriteMarkdown(failingWriter{}, referenceDescriptions()), "failure")
+}
@@ -0,0 +1,84 @@
+package envh
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// Secret wraps a sensitive config value, when using PopulateStruct* functions
+// it's populated like its underlying type but it's always rendered as
+// RedactionMask when printed or marshaled, so a config struct can be safely
+// dumped. Underlying value is only accessible through Reveal
+type Secret[T any] struct {
+	value T
+}
+
+// NewSecret creates a Secret wrapping a value
+func NewSecret[T any](value T) Secret[T] {
+	return Secret[T]{value}
+}
+
+// Reveal returns underlying value
+func (s Secret[T]) Reveal() T {
+	return s.value
+}
+
+// String returns RedactionMask
+func (s Secret[T]) String() string {
+	return RedactionMask
+}
+
+// GoString returns RedactionMask
+func (s Secret[T]) GoString() string {
+	return RedactionMask
+}
+
+// Format writes RedactionMask whatever verb is used
+func (s Secret[T]) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		fmt.Fprint(f, strconv.Quote(RedactionMask))
+
+		return
+	}
+
+	fmt.Fprint(f, RedactionMask)
+}
+
+// MarshalJSON renders RedactionMask as a JSON string
+func (s Secret[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(RedactionMask)
+}
+
+// MarshalText renders RedactionMask
+func (s Secret[T]) MarshalText() ([]byte, error) {
+	return []byte(RedactionMask), nil
+}
+
+func (s *Secret[T]) innerValue() reflect.Value {
+	return reflect.ValueOf(&s.value).Elem()
+}
+
+// secretValue is implemented by any Secret to let
+// population process set underlying value
+type secretValue interface {
+	innerValue() reflect.Value
+}
+
+var secretValueType = reflect.TypeOf((*secretValue)(nil)).Elem()
+
+// isSecretType returns true if type is a Secret
+func isSecretType(typ reflect.Type) bool {
+	return reflect.PtrTo(typ).Implements(secretValueType)
+}
+
+// unwrapSecret returns value wrapped by a Secret and true,
+// or given value and false if it's not a Secret
+func unwrapSecret(val reflect.Value) (reflect.Value, bool) {
+	if !val.CanAddr() || !isSecretType(val.Type()) {
+		return val, false
+	}
+
+	return val.Addr().Interface().(secretValue).innerValue(), true
+}
@@ -0,0 +1,39 @@
+package envh
+
+import (
+	"fmt"
+	"os"
+)
+
+func ExampleSecret() {
+	type ENVH struct {
+		DB struct {
+			USERNAME string
+			PASSWORD Secret[string]
+		}
+	}
+
+	os.Clearenv()
+	setEnv("ENVH_DB_USERNAME", "foo")
+	setEnv("ENVH_DB_PASSWORD", "bar")
+
+	env, err := NewEnvTree("^ENVH", "_")
+
+	if err != nil {
+		return
+	}
+
+	s := ENVH{}
+
+	err = env.PopulateStruct(&s)
+
+	if err != nil {
+		return
+	}
+
+	fmt.Printf("%+v\n", s)
+	fmt.Println(s.DB.PASSWORD.Reveal())
+	// Output:
+	// {DB:{USERNAME:foo PASSWORD:******}}
+	// bar
+}
@@ -0,0 +1,94 @@
+package envh
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecretRendering(t *testing.T) {
+	s := NewSecret("hunter2")
+
+	assert.Equal(t, "hunter2", s.Reveal())
+	assert.Equal(t, "******", s.String())
+	assert.Equal(t, "******", s.GoString())
+
+	for _, format := range []string{"%s", "%v", "%+v", "%#v", "%d", "%x"} {
+		assert.Equal(t, "******", fmt.Sprintf(format, s), format)
+	}
+
+	assert.Equal(t, `"******"`, fmt.Sprintf("%q", s))
+
+	b, err := json.Marshal(map[string]Secret[string]{"password": s})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"password":"******"}`, string(b))
+
+	b, err = s.MarshalText()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "******", string(b))
+}
+
+func TestPopulateStructWithSecrets(t *testing.T) {
+	type DB struct {
+		PASSWORD Secret[string] `validate:"min=8"`
+		PORT     Secret[int]
+		SSL      Secret[bool]
+		RATIO    Secret[float32]
+	}
+
+	type POPULATESTRUCT struct {
+		DB DB
+	}
+
+	setEnv("POPULATESTRUCT_DB_PASSWORD", "hunter2hunter2")
+	setEnv("POPULATESTRUCT_DB_PORT", "3306")
+	setEnv("POPULATESTRUCT_DB_SSL", "true")
+	setEnv("POPULATESTRUCT_DB_RATIO", "0.5")
+
+	actual := POPULATESTRUCT{}
+
+	tree, err := NewEnvTree("POPULATESTRUCT", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.PopulateStructWithStrictKeys(&actual)
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hunter2hunter2", actual.DB.PASSWORD.Reveal())
+	assert.Equal(t, 3306, actual.DB.PORT.Reveal())
+	assert.Equal(t, true, actual.DB.SSL.Reveal())
+	assert.Equal(t, float32(0.5), actual.DB.RATIO.Reveal())
+	assert.Equal(t, "{DB:{PASSWORD:****** PORT:****** SSL:****** RATIO:******}}", fmt.Sprintf("%+v", actual))
+}
+
+func TestPopulateStructWithSecretsTriggeringErrors(t *testing.T) {
+	type POPULATESTRUCT struct {
+		DSN   Secret[string] `validate:"url"`
+		PORT  Secret[int]
+		ITEMS Secret[[]string]
+	}
+
+	setEnv("POPULATESTRUCT_DSN", "hunter2")
+	setEnv("POPULATESTRUCT_PORT", "hunter2")
+
+	actual := POPULATESTRUCT{}
+
+	tree, err := NewEnvTree("POPULATESTRUCT", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.PopulateStruct(&actual)
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `3 error(s) occurred while populating struct :
+  - Field "DSN" : Value "******" of variable "POPULATESTRUCT_DSN" is invalid : must be a valid URL
+  - Field "PORT" : Value "******" of variable "POPULATESTRUCT_PORT" can't be converted to type "int"
+  - Field "ITEMS" : Type "slice" of variable "POPULATESTRUCT_ITEMS" is not supported : you must provide "int32, float32, string, boolean or struct"`)
+}
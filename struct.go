@@ -2,13 +2,15 @@ package envh
 
 import (
 	"reflect"
+	"strings"
 )
 
 // StructWalker must be implemented, when using PopulateStruct* functions,
 // to be able to set a value for a custom field with an unsupported field (a map for instance),
 // to add transformation before setting a field or for custom validation purpose.
 // Walk function is called when struct is populated for every struct field a matching is made with
-// an EnvTree node. Two parameters are given : tree represents whole parsed tree and keyChain is path leading to the node in tree.
+// an EnvTree node, unexported fields included, they're never set otherwise.
+// Two parameters are given : tree represents whole parsed tree and keyChain is path leading to the node in tree.
 // Returning true as first parameter will bypass walking process and false not, so it's
 // possible to completely control how some part of a structure are defined and it's possible as well
 // only to add some checking and let regular process do its job.
@@ -16,14 +18,30 @@ type StructWalker interface {
 	Walk(tree *EnvTree, keyChain []string) (bypassWalkingProcess bool, err error)
 }
 
+// StructValidator can be implemented by a struct, or any nested struct,
+// when using PopulateStruct* functions to check invariants once every field is set.
+// Nested structs are validated before the struct containing them.
+type StructValidator interface {
+	Validate() error
+}
+
+// StructFinalizer can be implemented by a struct, or any nested struct,
+// when using PopulateStruct* functions to define derived fields once every field is set.
+// Tree given is the sub tree matching the struct, AfterPopulate is called
+// before Validate and nested structs are finalized before the struct containing them.
+type StructFinalizer interface {
+	AfterPopulate(tree *EnvTree) error
+}
+
 type entry struct {
 	typ   reflect.Type
 	value reflect.Value
 	chain []string
+	path  []string
 }
 
-func populateInt(forceDefinition bool, tree *EnvTree, val reflect.Value, keyChain []string) error {
-	v, err := tree.FindInt(keyChain...)
+func populateInt(forceDefinition bool, val reflect.Value, lookup func() (string, bool), ref varRef) error {
+	v, err := getInt(lookup, ref)
 
 	if forceDefinition && err != nil {
 		return err
@@ -38,8 +56,8 @@ func populateInt(forceDefinition bool, tree *EnvTree, val reflect.Value, keyChai
 	return nil
 }
 
-func populateFloat(forceDefinition bool, tree *EnvTree, val reflect.Value, keyChain []string) error {
-	v, err := tree.FindFloat(keyChain...)
+func populateFloat(forceDefinition bool, val reflect.Value, lookup func() (string, bool), ref varRef) error {
+	v, err := getFloat(lookup, ref)
 
 	if forceDefinition && err != nil {
 		return err
@@ -54,8 +72,8 @@ func populateFloat(forceDefinition bool, tree *EnvTree, val reflect.Value, keyCh
 	return nil
 }
 
-func populateString(forceDefinition bool, tree *EnvTree, val reflect.Value, keyChain []string) error {
-	v, err := tree.FindString(keyChain...)
+func populateString(forceDefinition bool, val reflect.Value, lookup func() (string, bool), ref varRef) error {
+	v, err := getString(lookup, ref)
 
 	if forceDefinition && err != nil {
 		return err
@@ -66,8 +84,8 @@ func populateString(forceDefinition bool, tree *EnvTree, val reflect.Value, keyC
 	return nil
 }
 
-func populateBool(forceDefinition bool, tree *EnvTree, val reflect.Value, keyChain []string) error {
-	v, err := tree.FindBool(keyChain...)
+func populateBool(forceDefinition bool, val reflect.Value, lookup func() (string, bool), ref varRef) error {
+	v, err := getBool(lookup, ref)
 
 	if forceDefinition && err != nil {
 		return err
@@ -82,26 +100,58 @@ func populateBool(forceDefinition bool, tree *EnvTree, val reflect.Value, keyCha
 	return nil
 }
 
-func populateRegularType(entries *[]entry, tree *EnvTree, val reflect.Value, valKeyChain []string, forceDefinition bool) error {
-	switch val.Type().Kind() {
-	case reflect.Struct:
-		*entries = append(*entries, entry{val.Type(), val, valKeyChain})
+func populateWithDecoder(forceDefinition bool, val reflect.Value, lookup func() (string, bool), ref varRef, decode func(string) (interface{}, error)) error {
+	v, err := getString(lookup, ref)
+
+	if err != nil {
+		if forceDefinition {
+			return err
+		}
 
 		return nil
-	case reflect.Int:
-		return populateInt(forceDefinition, tree, val, valKeyChain)
-	case reflect.Float32:
-		return populateFloat(forceDefinition, tree, val, valKeyChain)
-	case reflect.String:
-		return populateString(forceDefinition, tree, val, valKeyChain)
-	case reflect.Bool:
-		return populateBool(forceDefinition, tree, val, valKeyChain)
-	default:
-		return TypeUnsupported{val.Type().Kind().String(), "int32, float32, string, boolean or struct"}
 	}
+
+	decoded, err := decode(v)
+
+	if err != nil {
+		return WrongTypeError{v, val.Type().String(), ref.keyChain, ref.name, err, false}
+	}
+
+	if decoded == nil {
+		// a decoder of an interface type can return a nil value
+		val.Set(reflect.Zero(val.Type()))
+
+		return nil
+	}
+
+	val.Set(reflect.ValueOf(decoded))
+
+	return nil
+}
+
+func populateRegularType(entries *[]entry, tree *EnvTree, val reflect.Value, valKeyChain []string, valPath []string, tag fieldTag, opts *populateOptions) error {
+	if _, ok := opts.decoder(val.Type()); !ok {
+		if inner, ok := unwrapSecret(val); ok {
+			return populateRegularType(entries, tree, inner, valKeyChain, valPath, tag, opts)
+		}
+
+		if val.Type().Kind() == reflect.Struct {
+			*entries = append(*entries, entry{val.Type(), val, opts.structKeyChain(valKeyChain), valPath})
+
+			return nil
+		}
+	}
+
+	return decodeValue(opts.forceDefinition, val, tag.lookup(tree, valKeyChain, opts.reports), tree.ref(valKeyChain), opts)
 }
 
-func callStructMethodWalk(origStruct interface{}, tree *EnvTree, keyChain []string) (bool, error) {
+func callStructMethodWalk(origStruct interface{}, tree *EnvTree, keyChain []string, opts *populateOptions) (bool, error) {
+	for _, walker := range opts.walkers {
+		if ok, err := walker.Walk(tree, keyChain); ok || err != nil {
+			return ok, err
+		}
+	}
+
 	if walker, ok := origStruct.(StructWalker); ok {
 		return walker.Walk(tree, keyChain)
 	}
@@ -109,60 +159,211 @@ func callStructMethodWalk(origStruct interface{}, tree *EnvTree, keyChain []stri
 	return false, nil
 }
 
-func populateStruct(entries *[]entry, origStruct interface{}, tree *EnvTree, forceDefinition bool) error {
+func populateStruct(entries *[]entry, origStruct interface{}, tree *EnvTree, opts *populateOptions, errs *[]FieldError, bypassed *[][]string) {
 	var err error
 	var ok bool
 	var val reflect.Value
 	var valKeyChain []string
+	var valPath []string
 
 	typ := (*entries)[0].typ
 	value := (*entries)[0].value
 	chain := (*entries)[0].chain
+	path := (*entries)[0].path
 
 	(*entries) = append([]entry{}, (*entries)[1:]...)
 
-	for i := 0; i < typ.NumField(); i++ {
-		val = value.Field(i)
-		valKeyChain = append([]string{}, append(chain, typ.Field(i).Name)...)
+	for _, f := range opts.plan(typ).walked {
+		val = value.Field(f.index)
+		tag := f.tag
+		key := tag.resolveKey(tree, chain)
+		valKeyChain = appendKey(chain, key)
+		valPath = appendKey(path, f.field.Name)
+
+		if !f.exported {
+			// an unexported field can't be set, it's only given to walkers
+			if ok, err = callStructMethodWalk(origStruct, tree, valKeyChain, opts); err != nil {
+				*errs = append(*errs, FieldError{valKeyChain, strings.Join(valPath, "."), err})
+			} else if ok {
+				*bypassed = append(*bypassed, valKeyChain)
+			}
+
+			continue
+		}
 
-		ok, err = callStructMethodWalk(origStruct, tree, valKeyChain)
+		if f.tagErr != nil {
+			*errs = append(*errs, FieldError{valKeyChain, strings.Join(valPath, "."), f.tagErr})
+
+			continue
+		}
+
+		reportDeprecatedField(tree, chain, key, tag, opts.reports)
+
+		ok, err = callStructMethodWalk(origStruct, tree, valKeyChain, opts)
 
 		if err != nil {
-			return err
+			*errs = append(*errs, FieldError{valKeyChain, strings.Join(valPath, "."), err})
+
+			continue
 		}
 
 		if ok {
+			*bypassed = append(*bypassed, valKeyChain)
+
 			continue
 		}
 
-		if err = populateRegularType(entries, tree, val, valKeyChain, forceDefinition); err != nil {
-			return err
+		switch set, isVariant := opts.variants[val.Type()]; {
+		case isVariant:
+			err = populateVariant(origStruct, tree, val, valKeyChain, valPath, tag, set, opts, errs, bypassed)
+		case isStructMap(val.Type(), opts):
+			err = populateStructMap(origStruct, tree, val, valKeyChain, valPath, opts, errs, bypassed)
+		default:
+			if err = populateRegularType(entries, tree, val, valKeyChain, valPath, tag, opts); err == nil {
+				recordSource(tree, val, valKeyChain, valPath, tag, opts)
+			}
+		}
+
+		if err != nil {
+			*errs = append(*errs, newFieldError(valKeyChain, valPath, tag, err))
+
+			continue
+		}
+
+		if err = validateField(tree, f, val, valKeyChain); err != nil {
+			*errs = append(*errs, newFieldError(valKeyChain, valPath, tag, err))
+		}
+	}
+}
+
+func newFieldError(keyChain []string, path []string, tag fieldTag, err error) FieldError {
+	if tag.secret {
+		err = markSecret(err)
+	}
+
+	return FieldError{keyChain, strings.Join(path, "."), err}
+}
+
+func callStructHooks(tree *EnvTree, value reflect.Value, chain []string, path []string, opts *populateOptions, errs *[]FieldError) {
+	for _, f := range opts.plan(value.Type()).fields {
+		if isNestedStruct(f.field.Type, opts) {
+			callStructHooks(tree, value.Field(f.index), opts.structKeyChain(appendKey(chain, f.tag.resolveKey(tree, chain))), appendKey(path, f.field.Name), opts, errs)
 		}
 	}
 
-	return nil
+	if hasFieldError(*errs, chain) {
+		return
+	}
+
+	var err error
+
+	if finalizer, ok := value.Addr().Interface().(StructFinalizer); ok {
+		subTree := findSubTreeOrEmpty(tree, chain)
+		err = finalizer.AfterPopulate(&subTree)
+	}
+
+	if validator, ok := value.Addr().Interface().(StructValidator); ok && err == nil {
+		err = validator.Validate()
+	}
+
+	if err != nil {
+		*errs = append(*errs, FieldError{chain, strings.Join(path, "."), err})
+	}
+}
+
+// findSubTreeOrEmpty returns sub tree matching key chain or an empty
+// one if it doesn't exist, so lookups return errors mentioning full variable names
+func findSubTreeOrEmpty(tree *EnvTree, chain []string) EnvTree {
+	if n, exists := tree.findNode(chain); exists {
+		return tree.subTree(n, chain)
+	}
+
+	return tree.subTree(newNode(), chain)
+}
+
+// hasFieldError returns true if an error was triggered
+// by a field living under given key chain
+func hasFieldError(errs []FieldError, chain []string) bool {
+	for _, err := range errs {
+		if len(err.KeyChain) >= len(chain) && reflect.DeepEqual(err.KeyChain[:len(chain)], chain) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isNestedStruct returns true if a field of this type is a struct
+// whose fields are populated one by one
+func isNestedStruct(typ reflect.Type, opts *populateOptions) bool {
+	_, hasDecoder := opts.decoder(typ)
+
+	return typ.Kind() == reflect.Struct && !isSecretType(typ) && !hasDecoder
+}
+
+// underlyingStructType returns struct type matching
+// a struct or a pointer to a struct
+func underlyingStructType(typ reflect.Type) (reflect.Type, bool) {
+	if typ == nil {
+		return nil, false
+	}
+
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	return typ, typ.Kind() == reflect.Struct
 }
 
 func isPointerToStruct(data interface{}) bool {
 	return !(reflect.TypeOf(data).Kind() != reflect.Ptr || reflect.TypeOf(data).Elem().Kind() != reflect.Struct)
 }
 
-func populateStructFromEnvTree(origStruct interface{}, tree *EnvTree, forceDefinition bool) error {
+// populateValue fills a struct value living at key chain, then checks rules
+// involving several fields and calls hooks of every struct it contains
+func populateValue(origStruct interface{}, tree *EnvTree, value reflect.Value, keyChain []string, path []string, opts *populateOptions, errs *[]FieldError, bypassed *[][]string) {
+	entries := []entry{{value.Type(), value, keyChain, path}}
+
+	for len(entries) > 0 {
+		populateStruct(&entries, origStruct, tree, opts, errs, bypassed)
+	}
+
+	validateCrossFields(tree, value, keyChain, path, opts, errs, *bypassed)
+	callStructHooks(tree, value, keyChain, path, opts, errs)
+}
+
+func populate(origStruct interface{}, tree *EnvTree, opts *populateOptions) error {
 	if !isPointerToStruct(origStruct) {
-		return TypeUnsupported{reflect.TypeOf(origStruct).Kind().String(), "pointer to struct"}
+		return TypeUnsupported{reflect.TypeOf(origStruct).Kind().String(), "pointer to struct", []string{}, ""}
 	}
 
-	entries := []entry{{reflect.TypeOf(origStruct).Elem(), reflect.ValueOf(origStruct).Elem(), []string{reflect.TypeOf(origStruct).Elem().Name()}}}
+	opts.reports = deprecationReports{}
+	keyChain := opts.rootKey(reflect.TypeOf(origStruct).Elem())
+	errs := []FieldError{}
+	bypassed := [][]string{}
+	unknownKeys := []UnknownKeyError{}
 
-	for {
-		err := populateStruct(&entries, origStruct, tree, forceDefinition)
+	populateValue(origStruct, tree, reflect.ValueOf(origStruct).Elem(), keyChain, []string{}, opts, &errs, &bypassed)
 
-		if err != nil {
-			return err
+	for _, hook := range opts.hooks {
+		if len(errs) > 0 {
+			break
 		}
 
-		if len(entries) == 0 {
-			return nil
+		subTree := findSubTreeOrEmpty(tree, keyChain)
+
+		if err := hook(&subTree, origStruct); err != nil {
+			errs = append(errs, FieldError{keyChain, "", err})
 		}
 	}
+
+	if opts.unknownKeys == ReportUnknownKeys {
+		unknownKeys = findUnknownKeys(tree, reflect.TypeOf(origStruct).Elem(), keyChain, bypassed, opts)
+	}
+
+	if len(errs) > 0 || len(unknownKeys) > 0 {
+		return PopulateError{errs, unknownKeys}
+	}
+
+	return nil
 }
@@ -0,0 +1,130 @@
+package envh
+
+import (
+	"os"
+	"testing"
+)
+
+type BENCHLEAF struct {
+	HOST    string `validate:"hostname"`
+	PORT    int    `default:"5432" validate:"port"`
+	RATIO   float32
+	ENABLED bool
+	NAME    string `envh:"NAME|TITLE"`
+}
+
+type BENCHLEVEL3 struct {
+	LEAF1 BENCHLEAF
+	LEAF2 BENCHLEAF
+	TOKEN string `envh:",secret" validate:"nonempty"`
+}
+
+type BENCHLEVEL2 struct {
+	LEVEL31 BENCHLEVEL3
+	LEVEL32 BENCHLEVEL3
+	MIN     int `validate:"ltfield=MAX"`
+	MAX     int
+}
+
+type BENCHCONFIG struct {
+	LEVEL21 BENCHLEVEL2
+	LEVEL22 BENCHLEVEL2
+	LEVEL   string `default:"info" validate:"oneof=debug info"`
+}
+
+func setBenchEnvs() {
+	os.Clearenv()
+
+	for _, l2 := range []string{"LEVEL21", "LEVEL22"} {
+		setEnv("BENCHCONFIG_"+l2+"_MIN", "1")
+		setEnv("BENCHCONFIG_"+l2+"_MAX", "2")
+
+		for _, l3 := range []string{"LEVEL31", "LEVEL32"} {
+			setEnv("BENCHCONFIG_"+l2+"_"+l3+"_TOKEN", "secret")
+
+			for _, leaf := range []string{"LEAF1", "LEAF2"} {
+				prefix := "BENCHCONFIG_" + l2 + "_" + l3 + "_" + leaf + "_"
+
+				setEnv(prefix+"HOST", "localhost")
+				setEnv(prefix+"PORT", "3306")
+				setEnv(prefix+"RATIO", "0.5")
+				setEnv(prefix+"ENABLED", "true")
+				setEnv(prefix+"TITLE", "bench")
+			}
+		}
+	}
+}
+
+func BenchmarkPopulateStruct(b *testing.B) {
+	setBenchEnvs()
+
+	tree, err := NewEnvTree("^BENCHCONFIG", "_")
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		config := BENCHCONFIG{}
+
+		if err := tree.PopulateStruct(&config); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.StopTimer()
+	restoreEnvs()
+}
+
+func BenchmarkPopulateStructWithStrictKeys(b *testing.B) {
+	setBenchEnvs()
+
+	tree, err := NewEnvTree("^BENCHCONFIG", "_")
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		config := BENCHCONFIG{}
+
+		if err := tree.PopulateStructWithStrictKeys(&config); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.StopTimer()
+	restoreEnvs()
+}
+
+func BenchmarkPopulateStructParallel(b *testing.B) {
+	setBenchEnvs()
+
+	tree, err := NewEnvTree("^BENCHCONFIG", "_")
+
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			config := BENCHCONFIG{}
+
+			if err := tree.PopulateStruct(&config); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.StopTimer()
+	restoreEnvs()
+}
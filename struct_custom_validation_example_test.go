@@ -77,5 +77,7 @@ func ExampleStructWalker_customValidation() {
 	err = env.PopulateStruct(&s)
 
 	fmt.Println(err)
-	// Output: "localhost" is not a valid IP change "CONFIG3_SERVER2_IP"
+	// Output:
+	// 1 error(s) occurred while populating struct :
+	//   - Field "SERVER2.IP" : "localhost" is not a valid IP change "CONFIG3_SERVER2_IP"
 }
@@ -0,0 +1,86 @@
+package envh
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+type CONFIG5 struct {
+	DB struct {
+		USERNAME string
+		PASSWORD string
+		HOST     string
+		NAME     string
+		PORT     int
+		URL      string
+	}
+}
+
+func (c *CONFIG5) AfterPopulate(tree *EnvTree) error {
+	c.DB.URL = fmt.Sprintf("jdbc:mysql://%s:%d/%s?user=%s&password=%s", c.DB.HOST, c.DB.PORT, c.DB.NAME, c.DB.USERNAME, c.DB.PASSWORD)
+
+	return nil
+}
+
+func (c *CONFIG5) Validate() error {
+	if c.DB.USERNAME == "root" {
+		return fmt.Errorf("connecting as root is forbidden")
+	}
+
+	return nil
+}
+
+func ExampleStructFinalizer() {
+	os.Clearenv()
+	setEnv("CONFIG5_DB_USERNAME", "foo")
+	setEnv("CONFIG5_DB_PASSWORD", "bar")
+	setEnv("CONFIG5_DB_HOST", "localhost")
+	setEnv("CONFIG5_DB_NAME", "my-db")
+	setEnv("CONFIG5_DB_PORT", "3306")
+
+	env, err := NewEnvTree("^CONFIG5", "_")
+
+	if err != nil {
+		return
+	}
+
+	s := CONFIG5{}
+
+	err = env.PopulateStruct(&s)
+
+	if err != nil {
+		return
+	}
+
+	b, err := json.Marshal(s)
+
+	if err != nil {
+		return
+	}
+
+	fmt.Println(string(b))
+	// Output:
+	// {"DB":{"USERNAME":"foo","PASSWORD":"bar","HOST":"localhost","NAME":"my-db","PORT":3306,"URL":"jdbc:mysql://localhost:3306/my-db?user=foo\u0026password=bar"}}
+}
+
+func ExampleStructValidator() {
+	os.Clearenv()
+	setEnv("CONFIG5_DB_USERNAME", "root")
+	setEnv("CONFIG5_DB_HOST", "localhost")
+
+	env, err := NewEnvTree("^CONFIG5", "_")
+
+	if err != nil {
+		return
+	}
+
+	s := CONFIG5{}
+
+	err = env.PopulateStruct(&s)
+
+	fmt.Println(err)
+	// Output:
+	// 1 error(s) occurred while populating struct :
+	//   - Struct "CONFIG5" : connecting as root is forbidden
+}
@@ -0,0 +1,61 @@
+package envh
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// isStructMap returns true if a field is a map of structs, or of pointers
+// to structs, indexed by strings whose entries are discovered from child keys
+func isStructMap(typ reflect.Type, opts *populateOptions) bool {
+	if opts.flat || typ.Kind() != reflect.Map || typ.Key().Kind() != reflect.String {
+		return false
+	}
+
+	if _, ok := opts.decoder(typ); ok {
+		return false
+	}
+
+	elem, ok := underlyingStructType(typ.Elem())
+
+	return ok && !isSecretType(elem)
+}
+
+// populateStructMap creates a map entry for every child key of node matching
+// the field, each entry is populated like any other struct, its key
+// is added to field path to know which entry triggered an error
+func populateStructMap(origStruct interface{}, tree *EnvTree, val reflect.Value, keyChain []string, path []string, opts *populateOptions, errs *[]FieldError, bypassed *[][]string) error {
+	keys, err := tree.FindChildrenKeys(keyChain...)
+
+	if err != nil {
+		if opts.forceDefinition {
+			return err
+		}
+
+		return nil
+	}
+
+	sort.Strings(keys)
+
+	structType, _ := underlyingStructType(val.Type().Elem())
+	m := reflect.MakeMapWithSize(val.Type(), len(keys))
+
+	for _, key := range keys {
+		ptr := reflect.New(structType)
+		entryKeyChain := append(append([]string{}, keyChain...), key)
+		entryPath := append(append([]string{}, path[:len(path)-1]...), fmt.Sprintf("%s[%s]", path[len(path)-1], key))
+
+		populateValue(origStruct, tree, ptr.Elem(), entryKeyChain, entryPath, opts, errs, bypassed)
+
+		if val.Type().Elem().Kind() == reflect.Ptr {
+			m.SetMapIndex(reflect.ValueOf(key).Convert(val.Type().Key()), ptr)
+		} else {
+			m.SetMapIndex(reflect.ValueOf(key).Convert(val.Type().Key()), ptr.Elem())
+		}
+	}
+
+	val.Set(m)
+
+	return nil
+}
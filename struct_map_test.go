@@ -0,0 +1,101 @@
+package envh
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type tenantConfig struct {
+	DB struct {
+		HOST string
+		PORT int `default:"5432" validate:"port"`
+	}
+	NAME      string
+	finalized bool
+}
+
+func (t *tenantConfig) AfterPopulate(tree *EnvTree) error {
+	t.finalized = true
+
+	return nil
+}
+
+type STRUCTMAP struct {
+	TENANTS map[string]tenantConfig
+	ADMINS  map[string]*struct {
+		EMAIL string
+	}
+}
+
+func TestPopulateStructMap(t *testing.T) {
+	setEnv("STRUCTMAP_TENANTS_ACME_DB_HOST", "acme.local")
+	setEnv("STRUCTMAP_TENANTS_ACME_NAME", "Acme")
+	setEnv("STRUCTMAP_TENANTS_GLOBEX_DB_HOST", "globex.local")
+	setEnv("STRUCTMAP_TENANTS_GLOBEX_DB_PORT", "5433")
+	setEnv("STRUCTMAP_ADMINS_ROOT_EMAIL", "root@localhost")
+
+	actual := STRUCTMAP{}
+
+	tree, err := NewEnvTree("^STRUCTMAP", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual)
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Len(t, actual.TENANTS, 2)
+	assert.Equal(t, "acme.local", actual.TENANTS["ACME"].DB.HOST)
+	assert.Equal(t, 5432, actual.TENANTS["ACME"].DB.PORT, "Defaults must be applied to every entry")
+	assert.Equal(t, "Acme", actual.TENANTS["ACME"].NAME)
+	assert.True(t, actual.TENANTS["ACME"].finalized, "Hooks must be called on every entry")
+	assert.Equal(t, "globex.local", actual.TENANTS["GLOBEX"].DB.HOST)
+	assert.Equal(t, 5433, actual.TENANTS["GLOBEX"].DB.PORT)
+	assert.Equal(t, "root@localhost", actual.ADMINS["ROOT"].EMAIL)
+}
+
+func TestPopulateStructMapWithErrors(t *testing.T) {
+	setEnv("STRUCTMAP_TENANTS_ACME_DB_HOST", "acme.local")
+	setEnv("STRUCTMAP_TENANTS_ACME_DB_PORT", "0")
+	setEnv("STRUCTMAP_TENANTS_GLOBEX_DB_HOST", "globex.local")
+	setEnv("STRUCTMAP_TENANTS_GLOBEX_NAME", "Globex")
+
+	actual := STRUCTMAP{}
+
+	tree, err := NewEnvTree("^STRUCTMAP", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithStrictMode())
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `3 error(s) occurred while populating struct :
+  - Field "TENANTS[ACME].NAME" : Variable "STRUCTMAP_TENANTS_ACME_NAME" not found
+  - Field "TENANTS[ACME].DB.PORT" : Value "0" of variable "STRUCTMAP_TENANTS_ACME_DB_PORT" is invalid : must be a valid port comprised between 1 and 65535
+  - Field "ADMINS" : No node found at path "STRUCTMAP -> ADMINS"`)
+	assert.True(t, errors.Is(err, ErrNodeNotFound))
+	assert.False(t, actual.TENANTS["ACME"].finalized, "Hooks must not be called on a failing entry")
+	assert.True(t, actual.TENANTS["GLOBEX"].finalized)
+}
+
+func TestPopulateStructMapWithUnknownKeys(t *testing.T) {
+	setEnv("STRUCTMAP_TENANTS_ACME_DB_HOST", "acme.local")
+	setEnv("STRUCTMAP_TENANTS_ACME_DB_HOTS", "acme.local")
+
+	actual := STRUCTMAP{}
+
+	tree, err := NewEnvTree("^STRUCTMAP", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithUnknownKeyPolicy(ReportUnknownKeys))
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Variable "STRUCTMAP_TENANTS_ACME_DB_HOTS" doesn't match any field, did you mean "STRUCTMAP_TENANTS_ACME_DB_HOST" ?`)
+}
@@ -0,0 +1,115 @@
+package envh
+
+import (
+	"reflect"
+	"sync"
+)
+
+// structPlan holds what population needs to know about fields of a struct type,
+// tags and rules are parsed once and the plan is shared by every population
+type structPlan struct {
+	fields []fieldPlan
+	// walked holds every field in declaration order, unexported ones
+	// can't be set and are only given to StructWalker
+	walked []fieldPlan
+	// byName gives position in fields of a field from its name
+	byName map[string]int
+}
+
+// fieldPlan describes a field of a struct
+type fieldPlan struct {
+	index    int
+	field    reflect.StructField
+	exported bool
+	tag      fieldTag
+	tagErr   error
+	rules    []rule
+	rulesErr error
+	// crossField is true if a rule involves a sibling field
+	crossField bool
+}
+
+// planKey identifies a plan, tags are parsed
+// differently depending on tag name and naming strategy
+type planKey struct {
+	typ     reflect.Type
+	tagName string
+	naming  uintptr
+}
+
+// structPlans caches plans of every struct type populated, it's safe for concurrent use
+var structPlans sync.Map
+
+// cachedNamings are naming strategies whose plans can be cached,
+// a custom strategy could be a closure whose behaviour can't be identified
+var cachedNamings = map[uintptr]bool{
+	reflect.ValueOf(IdentityNaming).Pointer():  true,
+	reflect.ValueOf(UpperCaseNaming).Pointer(): true,
+}
+
+// plan returns plan of a struct type, it's built on first use
+func (opts *populateOptions) plan(typ reflect.Type) *structPlan {
+	naming := reflect.ValueOf(opts.naming).Pointer()
+
+	if !cachedNamings[naming] {
+		return newStructPlan(typ, opts)
+	}
+
+	key := planKey{typ, opts.tagName, naming}
+
+	if p, ok := structPlans.Load(key); ok {
+		return p.(*structPlan)
+	}
+
+	p, _ := structPlans.LoadOrStore(key, newStructPlan(typ, opts))
+
+	return p.(*structPlan)
+}
+
+func newStructPlan(typ reflect.Type, opts *populateOptions) *structPlan {
+	p := &structPlan{[]fieldPlan{}, []fieldPlan{}, map[string]int{}}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		f := fieldPlan{index: i, field: field, exported: field.PkgPath == ""}
+		f.tag, f.tagErr = parseFieldTag(field, opts)
+
+		if !f.exported {
+			p.walked = append(p.walked, f)
+
+			continue
+		}
+
+		f.rules, f.rulesErr = parseValidationTag(field.Tag.Get(validationTagName))
+
+		for _, r := range f.rules {
+			f.crossField = f.crossField || isCrossFieldRule(r.name)
+		}
+
+		p.byName[field.Name] = len(p.fields)
+		p.fields = append(p.fields, f)
+		p.walked = append(p.walked, f)
+	}
+
+	return p
+}
+
+// field returns plan of an exported field declared in struct
+func (p *structPlan) field(name string) (fieldPlan, bool) {
+	i, ok := p.byName[name]
+
+	if !ok {
+		return fieldPlan{}, false
+	}
+
+	return p.fields[i], true
+}
+
+// appendKey returns a new key chain made of chain followed by key
+func appendKey(chain []string, key string) []string {
+	keyChain := make([]string, len(chain)+1)
+	copy(keyChain, chain)
+	keyChain[len(chain)] = key
+
+	return keyChain
+}
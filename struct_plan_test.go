@@ -0,0 +1,104 @@
+package envh
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStructPlan(t *testing.T) {
+	type PLAN struct {
+		NAME     string `envh:"NAME|TITLE,secret"`
+		PORT     int    `default:"80" validate:"port"`
+		MIN      int    `validate:"ltfield=PORT"`
+		BROKEN   string `envh:",whatever" validate:"unknown"`
+		internal string
+	}
+
+	p := newPopulateOptions().plan(reflect.TypeOf(PLAN{}))
+
+	assert.Len(t, p.fields, 4)
+	assert.Equal(t, fieldTag{name: "NAME", keys: []string{"NAME", "TITLE"}, secret: true}, p.fields[0].tag)
+	assert.Equal(t, []rule{{"port", ""}}, p.fields[1].rules)
+	assert.False(t, p.fields[1].crossField)
+	assert.True(t, p.fields[2].crossField)
+	assert.EqualError(t, p.fields[3].tagErr, `Tag envh:",whatever" is invalid : option "whatever" doesn't exist`)
+	assert.EqualError(t, p.fields[3].rulesErr, `Tag validate:"unknown" is invalid : rule "unknown" doesn't exist`)
+
+	f, ok := p.field("PORT")
+
+	assert.True(t, ok)
+	assert.Equal(t, 1, f.index)
+
+	_, ok = p.field("internal")
+
+	assert.False(t, ok, "Must not find an unexported field")
+	assert.True(t, p == newPopulateOptions().plan(reflect.TypeOf(PLAN{})), "Must reuse plan of a type")
+	assert.False(t, p == newPopulateOptions(WithTagName("env")).plan(reflect.TypeOf(PLAN{})), "Must build a plan per tag name")
+	assert.False(t, p == newPopulateOptions(WithNamingStrategy(UpperCaseNaming)).plan(reflect.TypeOf(PLAN{})), "Must build a plan per naming strategy")
+
+	prefix := "APP_"
+	naming := func(fieldName string) string {
+		return prefix + fieldName
+	}
+	custom := newPopulateOptions(WithNamingStrategy(naming))
+
+	assert.Equal(t, "APP_PORT", custom.plan(reflect.TypeOf(PLAN{})).fields[1].tag.name)
+
+	prefix = "SRV_"
+
+	assert.Equal(t, "SRV_PORT", custom.plan(reflect.TypeOf(PLAN{})).fields[1].tag.name, "Must not cache plans of a custom naming strategy")
+}
+
+func TestPopulateStructConcurrently(t *testing.T) {
+	setBenchEnvs()
+
+	tree, err := NewEnvTree("^BENCHCONFIG", "_")
+
+	assert.NoError(t, err)
+
+	expected := BENCHCONFIG{}
+
+	assert.NoError(t, tree.PopulateStruct(&expected))
+	assert.Equal(t, "bench", expected.LEVEL22.LEVEL32.LEAF2.NAME)
+	assert.Equal(t, 3306, expected.LEVEL21.LEVEL31.LEAF1.PORT)
+
+	wg := sync.WaitGroup{}
+	configs := make([]BENCHCONFIG, 20)
+	errs := make([]error, 20)
+
+	for i := range configs {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			errs[i] = tree.PopulateStruct(&configs[i])
+		}(i)
+	}
+
+	wg.Wait()
+
+	for i := range configs {
+		assert.NoError(t, errs[i])
+		assert.Equal(t, expected, configs[i])
+	}
+
+	restoreEnvs()
+}
+
+func TestAppendKey(t *testing.T) {
+	chain := make([]string, 2, 10)
+	copy(chain, []string{"A", "B"})
+
+	first := appendKey(chain, "C")
+	second := appendKey(chain, "D")
+
+	assert.Equal(t, []string{"A", "B", "C"}, first)
+	assert.Equal(t, []string{"A", "B", "D"}, second)
+	assert.Equal(t, "A_B", strings.Join(chain, "_"))
+	assert.Equal(t, 3, cap(first))
+}
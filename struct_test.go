@@ -1,6 +1,7 @@
 package envh
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -15,11 +16,11 @@ func TestPopulateStructWithNoPointersGiven(t *testing.T) {
 
 	assert.NoError(t, err, "Must return no errors")
 
-	err = populateStructFromEnvTree(POPULATESTRUCT{}, &tree, false)
+	err = tree.Populate(POPULATESTRUCT{})
 
 	assert.EqualError(t, err, `Type "struct" is not supported : you must provide "pointer to struct"`)
 
-	err = populateStructFromEnvTree(8, &tree, false)
+	err = tree.Populate(8)
 
 	assert.EqualError(t, err, `Type "int" is not supported : you must provide "pointer to struct"`)
 }
@@ -48,11 +49,12 @@ func TestPopulateStructWithInnerPointer(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	err = populateStructFromEnvTree(&actual, &tree, false)
+	err = tree.Populate(&actual)
 
 	restoreEnvs()
 
-	assert.EqualError(t, err, `Type "ptr" is not supported : you must provide "int32, float32, string, boolean or struct"`)
+	assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "TEST3.TEST5" : Type "ptr" of variable "POPULATESTRUCT_TEST3_TEST5" is not supported : you must provide "int32, float32, string, boolean or struct"`)
 }
 
 func TestPopulateStructWithTypeErrors(t *testing.T) {
@@ -78,7 +80,8 @@ func TestPopulateStructWithTypeErrors(t *testing.T) {
 				setEnv("POPULATESTRUCT_TEST1", "value1")
 			},
 			checkError: func(err error) {
-				assert.EqualError(t, err, `Value "value1" can't be converted to type "float"`)
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "TEST1" : Value "value1" of variable "POPULATESTRUCT_TEST1" can't be converted to type "float"`)
 			},
 		},
 		{
@@ -86,7 +89,8 @@ func TestPopulateStructWithTypeErrors(t *testing.T) {
 				setEnv("POPULATESTRUCT_TEST2", "value2")
 			},
 			checkError: func(err error) {
-				assert.EqualError(t, err, `Value "value2" can't be converted to type "int"`)
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "TEST2" : Value "value2" of variable "POPULATESTRUCT_TEST2" can't be converted to type "int"`)
 			},
 		},
 		{
@@ -94,7 +98,8 @@ func TestPopulateStructWithTypeErrors(t *testing.T) {
 				setEnv("POPULATESTRUCT_TEST3", "value3")
 			},
 			checkError: func(err error) {
-				assert.EqualError(t, err, `Value "value3" can't be converted to type "bool"`)
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "TEST3" : Value "value3" of variable "POPULATESTRUCT_TEST3" can't be converted to type "bool"`)
 			},
 		},
 		{
@@ -102,7 +107,8 @@ func TestPopulateStructWithTypeErrors(t *testing.T) {
 				setEnv("POPULATESTRUCT_TEST4_TEST6", "value4")
 			},
 			checkError: func(err error) {
-				assert.EqualError(t, err, `Value "value4" can't be converted to type "int"`)
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "TEST4.TEST6" : Value "value4" of variable "POPULATESTRUCT_TEST4_TEST6" can't be converted to type "int"`)
 			},
 		},
 	}
@@ -116,7 +122,7 @@ func TestPopulateStructWithTypeErrors(t *testing.T) {
 
 		assert.NoError(t, err)
 
-		err = populateStructFromEnvTree(&actual, &tree, false)
+		err = tree.Populate(&actual)
 		s.checkError(err)
 		restoreEnvs()
 	}
@@ -152,7 +158,7 @@ func TestPopulateStructWithStrictCheckDisabled(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	err = populateStructFromEnvTree(&actual, &tree, false)
+	err = tree.Populate(&actual)
 
 	restoreEnvs()
 
@@ -191,7 +197,11 @@ func TestPopulateStructWithStrictCheckEnabled(t *testing.T) {
 			init: func() {
 			},
 			checkError: func(err error) {
-				assert.EqualError(t, err, `Variable not found`)
+				assert.EqualError(t, err, `4 error(s) occurred while populating struct :
+  - Field "TEST8" : Variable "POPULATESTRUCT_TEST8" not found
+  - Field "TEST9" : Variable "POPULATESTRUCT_TEST9" not found
+  - Field "TEST10" : Variable "POPULATESTRUCT_TEST10" not found
+  - Field "TEST11" : Variable "POPULATESTRUCT_TEST11" not found`)
 			},
 		},
 		{
@@ -199,7 +209,10 @@ func TestPopulateStructWithStrictCheckEnabled(t *testing.T) {
 				setEnv("POPULATESTRUCT_TEST8", "1")
 			},
 			checkError: func(err error) {
-				assert.EqualError(t, err, `Variable not found`)
+				assert.EqualError(t, err, `3 error(s) occurred while populating struct :
+  - Field "TEST9" : Variable "POPULATESTRUCT_TEST9" not found
+  - Field "TEST10" : Variable "POPULATESTRUCT_TEST10" not found
+  - Field "TEST11" : Variable "POPULATESTRUCT_TEST11" not found`)
 			},
 		},
 		{
@@ -208,7 +221,9 @@ func TestPopulateStructWithStrictCheckEnabled(t *testing.T) {
 				setEnv("POPULATESTRUCT_TEST9", "1.1")
 			},
 			checkError: func(err error) {
-				assert.EqualError(t, err, `Variable not found`)
+				assert.EqualError(t, err, `2 error(s) occurred while populating struct :
+  - Field "TEST10" : Variable "POPULATESTRUCT_TEST10" not found
+  - Field "TEST11" : Variable "POPULATESTRUCT_TEST11" not found`)
 			},
 		},
 		{
@@ -218,7 +233,8 @@ func TestPopulateStructWithStrictCheckEnabled(t *testing.T) {
 				setEnv("POPULATESTRUCT_TEST10", "test")
 			},
 			checkError: func(err error) {
-				assert.EqualError(t, err, `Variable not found`)
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "TEST11" : Variable "POPULATESTRUCT_TEST11" not found`)
 			},
 		},
 	}
@@ -232,7 +248,7 @@ func TestPopulateStructWithStrictCheckEnabled(t *testing.T) {
 
 		assert.NoError(t, err)
 
-		err = populateStructFromEnvTree(&actual, &tree, true)
+		err = tree.Populate(&actual, WithStrictMode())
 		s.checkError(err)
 		restoreEnvs()
 	}
@@ -298,7 +314,7 @@ func TestPopulateStructWithCustomSet(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	err = populateStructFromEnvTree(&actual, &tree, false)
+	err = tree.Populate(&actual)
 
 	assert.NoError(t, err)
 
@@ -309,6 +325,42 @@ func TestPopulateStructWithCustomSet(t *testing.T) {
 	restoreEnvs()
 }
 
+type UNEXPORTEDWALK struct {
+	HOST    string
+	timeout int
+	port    int
+}
+
+func (u *UNEXPORTEDWALK) Walk(tree *EnvTree, keyChain []string) (bool, error) {
+	if strings.Join(keyChain, "_") != "UNEXPORTEDWALK_timeout" {
+		return false, nil
+	}
+
+	timeout, err := tree.FindInt(keyChain...)
+	u.timeout = timeout
+
+	return true, err
+}
+
+func TestPopulateStructWithUnexportedFieldsGivenToWalker(t *testing.T) {
+	setEnv("UNEXPORTEDWALK_HOST", "localhost")
+	setEnv("UNEXPORTEDWALK_timeout", "30")
+	setEnv("UNEXPORTEDWALK_port", "8080")
+
+	actual := UNEXPORTEDWALK{}
+
+	tree, err := NewEnvTree("UNEXPORTEDWALK", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual)
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, UNEXPORTEDWALK{HOST: "localhost", timeout: 30}, actual, "Must give unexported fields to walker and never set them otherwise")
+}
+
 func TestPopulateStructWithCustomSetTriggeringAnError(t *testing.T) {
 	setEnv("SUM_LEFTOPERAND", "2")
 
@@ -318,9 +370,10 @@ func TestPopulateStructWithCustomSetTriggeringAnError(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	err = populateStructFromEnvTree(&actual, &tree, false)
+	err = tree.Populate(&actual)
 
-	assert.EqualError(t, err, `Can't find "SUM_LEFTOPERAND"`, "Must bubble up an error from Populate function")
+	assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "RESULT" : Can't find "SUM_LEFTOPERAND"`, "Must bubble up an error from Populate function")
 
 	restoreEnvs()
 }
@@ -335,9 +388,10 @@ func TestPopulateStructWithCustomValidationTriggeringAnError(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	err = populateStructFromEnvTree(&actual, &tree, false)
+	err = tree.Populate(&actual)
 
-	assert.EqualError(t, err, `"LEFTOPERAND" must be greater than 0`, "Must validate data")
+	assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "LEFTOPERAND" : "LEFTOPERAND" must be greater than 0`, "Must validate data")
 
 	restoreEnvs()
 }
@@ -387,7 +441,7 @@ func TestFixReferenceMessUp(t *testing.T) {
 
 	assert.NoError(t, err)
 
-	err = populateStructFromEnvTree(&actual, &tree, false)
+	err = tree.Populate(&actual)
 
 	assert.NoError(t, err)
 
@@ -400,3 +454,330 @@ func TestFixReferenceMessUp(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestPopulateStructAggregateErrors(t *testing.T) {
+	type TEST4 struct {
+		TEST5 bool
+	}
+
+	type POPULATESTRUCT struct {
+		TEST1 int
+		TEST2 float32
+		TEST3 string
+		TEST4 TEST4
+		TEST6 map[string]string
+	}
+
+	setEnv("POPULATESTRUCT_TEST1", "value1")
+	setEnv("POPULATESTRUCT_TEST2", "1.5")
+	setEnv("POPULATESTRUCT_TEST4_TEST5", "value5")
+
+	actual := POPULATESTRUCT{}
+
+	tree, err := NewEnvTree("POPULATESTRUCT", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithStrictMode())
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `4 error(s) occurred while populating struct :
+  - Field "TEST1" : Value "value1" of variable "POPULATESTRUCT_TEST1" can't be converted to type "int"
+  - Field "TEST3" : Variable "POPULATESTRUCT_TEST3" not found
+  - Field "TEST6" : Type "map" of variable "POPULATESTRUCT_TEST6" is not supported : you must provide "int32, float32, string, boolean or struct"
+  - Field "TEST4.TEST5" : Value "value5" of variable "POPULATESTRUCT_TEST4_TEST5" can't be converted to type "bool"`)
+	assert.Equal(t, float32(1.5), actual.TEST2, "Must populate valid fields even if other ones failed")
+
+	populateErr := PopulateError{}
+
+	assert.True(t, errors.As(err, &populateErr))
+	assert.Len(t, populateErr.Errors, 4)
+	assert.Equal(t, []string{"POPULATESTRUCT", "TEST4", "TEST5"}, populateErr.Errors[3].KeyChain)
+
+	wrongTypeErr := WrongTypeError{}
+
+	assert.True(t, errors.As(err, &wrongTypeErr))
+	assert.Equal(t, "value1", wrongTypeErr.Value)
+	assert.True(t, errors.Is(err, ErrVariableNotFound))
+	assert.True(t, errors.Is(err, ErrWrongType))
+	assert.True(t, errors.Is(err, ErrTypeUnsupported))
+	assert.False(t, errors.Is(err, ErrNodeNotFound))
+	assert.Equal(t, "TEST4.TEST5", populateErr.Errors[3].Path)
+}
+
+func TestPopulateStructWithValidationTags(t *testing.T) {
+	type SERVER struct {
+		HOST string `validate:"hostname"`
+		PORT int    `validate:"port"`
+	}
+
+	type POPULATESTRUCT struct {
+		SERVER SERVER
+		LEVEL  string `validate:"oneof=debug info"`
+		NAME   string `validate:"nonempty,max=5"`
+		RETRY  int    `validate:"min=1"`
+		TAG    string `validate:"unknown"`
+	}
+
+	setEnv("POPULATESTRUCT_SERVER_HOST", "-localhost")
+	setEnv("POPULATESTRUCT_SERVER_PORT", "0")
+	setEnv("POPULATESTRUCT_LEVEL", "info")
+	setEnv("POPULATESTRUCT_NAME", "envh-test")
+
+	actual := POPULATESTRUCT{}
+
+	tree, err := NewEnvTree("POPULATESTRUCT", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual)
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `4 error(s) occurred while populating struct :
+  - Field "NAME" : Value "envh-test" of variable "POPULATESTRUCT_NAME" is invalid : length must be lower than or equal to 5
+  - Field "TAG" : Tag validate:"unknown" is invalid : rule "unknown" doesn't exist
+  - Field "SERVER.HOST" : Value "-localhost" of variable "POPULATESTRUCT_SERVER_HOST" is invalid : must be a valid hostname
+  - Field "SERVER.PORT" : Value "0" of variable "POPULATESTRUCT_SERVER_PORT" is invalid : must be a valid port comprised between 1 and 65535`)
+
+	validationErr := ValidationError{}
+
+	assert.True(t, errors.As(err, &validationErr))
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.Equal(t, "max", validationErr.Rule)
+	assert.Equal(t, "5", validationErr.Param)
+	assert.Equal(t, []string{"POPULATESTRUCT", "NAME"}, validationErr.KeyChain)
+	assert.Equal(t, "info", actual.LEVEL)
+}
+
+func TestPopulateStructWithNonEmptyOnMissingVariable(t *testing.T) {
+	type POPULATESTRUCT struct {
+		NAME string `validate:"nonempty"`
+	}
+
+	setEnv("POPULATESTRUCT_LEVEL", "info")
+
+	tree, err := NewEnvTree("POPULATESTRUCT", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&POPULATESTRUCT{})
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&POPULATESTRUCT{}, WithStrictMode())
+
+	restoreEnvs()
+
+	assert.True(t, errors.Is(err, ErrVariableNotFound))
+	assert.False(t, errors.Is(err, ErrValidation))
+}
+
+type HOOKDB struct {
+	HOST string
+	PORT int
+	URL  string
+}
+
+func (d *HOOKDB) AfterPopulate(tree *EnvTree) error {
+	d.URL = fmt.Sprintf("%s:%d", d.HOST, d.PORT)
+
+	return nil
+}
+
+func (d *HOOKDB) Validate() error {
+	if d.HOST == "" {
+		return fmt.Errorf(`host can't be empty`)
+	}
+
+	return nil
+}
+
+type HOOK struct {
+	PRIMARY HOOKDB
+	REPLICA HOOKDB
+	NAME    string
+	calls   []string
+}
+
+func (h *HOOK) AfterPopulate(tree *EnvTree) error {
+	name, err := tree.FindString("NAME")
+
+	if err != nil {
+		return err
+	}
+
+	h.calls = append(h.calls, "after populate "+name+" "+h.PRIMARY.URL)
+
+	return nil
+}
+
+func (h *HOOK) Validate() error {
+	h.calls = append(h.calls, "validate")
+
+	if h.PRIMARY.URL == h.REPLICA.URL {
+		return fmt.Errorf(`primary and replica must be different`)
+	}
+
+	return nil
+}
+
+func TestPopulateStructWithHooks(t *testing.T) {
+	setEnv("HOOK_NAME", "test")
+	setEnv("HOOK_PRIMARY_HOST", "localhost")
+	setEnv("HOOK_PRIMARY_PORT", "3306")
+	setEnv("HOOK_REPLICA_HOST", "localhost")
+	setEnv("HOOK_REPLICA_PORT", "3307")
+
+	actual := HOOK{}
+
+	tree, err := NewEnvTree("HOOK", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual)
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "localhost:3306", actual.PRIMARY.URL)
+	assert.Equal(t, "localhost:3307", actual.REPLICA.URL)
+	assert.Equal(t, []string{"after populate test localhost:3306", "validate"}, actual.calls, "Must call nested struct hooks first")
+}
+
+func TestPopulateStructWithHooksTriggeringErrors(t *testing.T) {
+	type g struct {
+		init       func()
+		checkError func(err error)
+	}
+
+	tests := []g{
+		{
+			init: func() {
+				setEnv("HOOK_NAME", "test")
+				setEnv("HOOK_PRIMARY_HOST", "localhost")
+				setEnv("HOOK_PRIMARY_PORT", "3306")
+				setEnv("HOOK_REPLICA_HOST", "localhost")
+				setEnv("HOOK_REPLICA_PORT", "3306")
+			},
+			checkError: func(err error) {
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Struct "HOOK" : primary and replica must be different`)
+			},
+		},
+		{
+			init: func() {
+				setEnv("HOOK_PRIMARY_HOST", "localhost")
+			},
+			checkError: func(err error) {
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "REPLICA" : host can't be empty`, "Must not call hooks of a struct having an invalid nested struct")
+			},
+		},
+		{
+			init: func() {
+				setEnv("HOOK_PRIMARY_HOST", "localhost")
+				setEnv("HOOK_REPLICA_HOST", "127.0.0.1")
+			},
+			checkError: func(err error) {
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Struct "HOOK" : Variable "HOOK_NAME" not found`)
+			},
+		},
+		{
+			init: func() {
+				setEnv("HOOK_NAME", "test")
+				setEnv("HOOK_PRIMARY_HOST", "localhost")
+				setEnv("HOOK_PRIMARY_PORT", "whatever")
+				setEnv("HOOK_REPLICA_HOST", "localhost")
+			},
+			checkError: func(err error) {
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "PRIMARY.PORT" : Value "whatever" of variable "HOOK_PRIMARY_PORT" can't be converted to type "int"`, "Must not call hooks of a struct having invalid fields")
+			},
+		},
+	}
+
+	for _, s := range tests {
+		actual := HOOK{}
+
+		s.init()
+
+		tree, err := NewEnvTree("HOOK", "_")
+
+		assert.NoError(t, err)
+
+		err = tree.Populate(&actual)
+		s.checkError(err)
+		restoreEnvs()
+	}
+}
+
+func TestPopulateStructWithFieldTags(t *testing.T) {
+	type DATABASE struct {
+		DSN  string `envh:",secret" validate:"url"`
+		PORT int    `envh:",secret"`
+		PASS int
+	}
+
+	type POPULATESTRUCT struct {
+		Database DATABASE `envh:"DB"`
+		Name     string   `envh:"NAME"`
+		Test     string   `envh:",unknown"`
+	}
+
+	setEnv("POPULATESTRUCT_DB_DSN", "user:hunter2@localhost")
+	setEnv("POPULATESTRUCT_DB_PORT", "hunter2")
+	setEnv("POPULATESTRUCT_DB_PASS", "hunter2")
+	setEnv("POPULATESTRUCT_NAME", "envh")
+
+	actual := POPULATESTRUCT{}
+
+	tree, err := NewEnvTree("POPULATESTRUCT", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual)
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `4 error(s) occurred while populating struct :
+  - Field "Test" : Tag envh:",unknown" is invalid : option "unknown" doesn't exist
+  - Field "Database.DSN" : Value "******" of variable "POPULATESTRUCT_DB_DSN" is invalid : must be a valid URL
+  - Field "Database.PORT" : Value "******" of variable "POPULATESTRUCT_DB_PORT" can't be converted to type "int"
+  - Field "Database.PASS" : Value "******" of variable "POPULATESTRUCT_DB_PASS" can't be converted to type "int"`)
+	assert.Equal(t, "envh", actual.Name)
+	assert.NotContains(t, err.Error(), "hunter2")
+}
+
+func TestPopulateStructWithDefaultTags(t *testing.T) {
+	type DEFAULTS struct {
+		HOST    string `default:"localhost"`
+		PORT    int    `default:"3306" validate:"port"`
+		RATIO   float32
+		DEBUG   bool `default:"true"`
+		TIMEOUT int  `default:"whatever"`
+		RETRIES int  `default:"0" validate:"min=1"`
+	}
+
+	setEnv("DEFAULTS_HOST", "127.0.0.1")
+
+	actual := DEFAULTS{}
+
+	tree, err := NewEnvTree("^DEFAULTS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithStrictMode())
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `3 error(s) occurred while populating struct :
+  - Field "RATIO" : Variable "DEFAULTS_RATIO" not found
+  - Field "TIMEOUT" : Value "whatever" of variable "DEFAULTS_TIMEOUT" can't be converted to type "int"
+  - Field "RETRIES" : Value "0" of variable "DEFAULTS_RETRIES" is invalid : must be greater than or equal to 1`)
+	assert.Equal(t, "127.0.0.1", actual.HOST, "Variable must take precedence over default")
+	assert.Equal(t, 3306, actual.PORT)
+	assert.True(t, actual.DEBUG)
+}
@@ -0,0 +1,43 @@
+package envh
+
+import (
+	"fmt"
+	"os"
+)
+
+type CONFIG4 struct {
+	SERVER1 struct {
+		IP   string `validate:"ip"`
+		PORT int    `validate:"port"`
+	}
+	SERVER2 struct {
+		IP   string `validate:"ip"`
+		PORT int    `validate:"port"`
+	}
+	LOGLEVEL string `validate:"oneof=debug info warn error"`
+}
+
+func ExampleEnvTree_PopulateStruct_validationTags() {
+	os.Clearenv()
+	setEnv("CONFIG4_SERVER1_IP", "127.0.0.1")
+	setEnv("CONFIG4_SERVER1_PORT", "3000")
+	setEnv("CONFIG4_SERVER2_IP", "localhost")
+	setEnv("CONFIG4_SERVER2_PORT", "70000")
+	setEnv("CONFIG4_LOGLEVEL", "info")
+
+	env, err := NewEnvTree("^CONFIG4", "_")
+
+	if err != nil {
+		return
+	}
+
+	s := CONFIG4{}
+
+	err = env.PopulateStruct(&s)
+
+	fmt.Println(err)
+	// Output:
+	// 2 error(s) occurred while populating struct :
+	//   - Field "SERVER2.IP" : Value "localhost" of variable "CONFIG4_SERVER2_IP" is invalid : must be a valid IP address
+	//   - Field "SERVER2.PORT" : Value "70000" of variable "CONFIG4_SERVER2_PORT" is invalid : must be a valid port comprised between 1 and 65535
+}
@@ -0,0 +1,104 @@
+package envh
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/antham/envh/internal/tagspec"
+)
+
+const tagName = "envh"
+
+const envTagName = "env"
+
+const defaultTagName = "default"
+
+const deprecatedTagName = "deprecated"
+
+const descriptionTagName = "description"
+
+// fieldTag holds settings defined in an envh struct tag,
+// for instance `envh:"PASSWORD,secret"`, first element
+// overrides key matching the field, an empty one keeps field name
+// transformed by naming strategy. Several candidate keys can be
+// separated by pipes (`envh:"DATABASE_URL|DB_URL"`), first one defined wins.
+// A Secret field is always considered as secret.
+// A value used when variable is missing can be defined
+// in a default tag, for instance `default:"8080"`, a field
+// can be marked as deprecated with a message in a deprecated tag
+type fieldTag struct {
+	name               string
+	keys               []string
+	secret             bool
+	defaultValue       string
+	hasDefault         bool
+	deprecationMessage string
+	deprecated         bool
+}
+
+func parseFieldTag(field reflect.StructField, opts *populateOptions) (fieldTag, error) {
+	tag := fieldTag{name: opts.naming(field.Name), secret: isSecretType(field.Type)}
+	tag.keys = []string{tag.name}
+	tag.defaultValue, tag.hasDefault = field.Tag.Lookup(defaultTagName)
+	tag.deprecationMessage, tag.deprecated = field.Tag.Lookup(deprecatedTagName)
+	value, ok := field.Tag.Lookup(opts.tagName)
+
+	if !ok {
+		return tag, nil
+	}
+
+	options := strings.Split(value, ",")
+
+	if keys := tagspec.ParseKeys(options[0]); len(keys) > 0 {
+		tag.name = keys[0]
+		tag.keys = keys
+	}
+
+	for _, option := range options[1:] {
+		switch strings.TrimSpace(option) {
+		case tagspec.SecretOption:
+			tag.secret = true
+		default:
+			return fieldTag{name: opts.naming(field.Name), keys: []string{opts.naming(field.Name)}, secret: isSecretType(field.Type), defaultValue: tag.defaultValue, hasDefault: tag.hasDefault}, TagError{opts.tagName, value, fmt.Sprintf(`option "%s" doesn't exist`, option)}
+		}
+	}
+
+	return tag, nil
+}
+
+// resolveKey returns first candidate key existing in tree below key chain,
+// first candidate is returned if none exists
+func (t fieldTag) resolveKey(tree *EnvTree, chain []string) string {
+	if len(t.keys) < 2 {
+		return t.name
+	}
+
+	for _, key := range t.keys {
+		if tree.IsExistingSubTree(appendKey(chain, key)...) {
+			return key
+		}
+	}
+
+	return t.name
+}
+
+// lookup returns a function giving value of variable matching key chain,
+// default value is used when variable doesn't exist
+func (t fieldTag) lookup(tree *EnvTree, keyChain []string, reports deprecationReports) func() (string, bool) {
+	fun := tree.reportingLookup(keyChain, reports)
+
+	return func() (string, bool) {
+		if v, ok := fun(); ok {
+			return v, true
+		}
+
+		return t.defaultValue, t.hasDefault
+	}
+}
+
+// isDefined returns true if a value is defined
+// for the field, either in tree or with a default
+func (t fieldTag) isDefined(tree *EnvTree, keyChain []string) bool {
+	return t.hasDefault || tree.HasSubTreeValueUnsecured(keyChain...)
+}
@@ -0,0 +1,47 @@
+package envh
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFieldTag(t *testing.T) {
+	type TEST struct {
+		TEST1 string
+		TEST2 string `envh:"NAME"`
+		TEST3 string `envh:",secret"`
+		TEST4 string `envh:"NAME, secret"`
+		TEST5 string `envh:",whatever"`
+		TEST6 string `envh:"NAME | OLD_NAME|,secret"`
+	}
+
+	type g struct {
+		field    string
+		expected fieldTag
+		err      string
+	}
+
+	tests := []g{
+		{"TEST1", fieldTag{name: "TEST1", keys: []string{"TEST1"}}, ""},
+		{"TEST2", fieldTag{name: "NAME", keys: []string{"NAME"}}, ""},
+		{"TEST3", fieldTag{name: "TEST3", keys: []string{"TEST3"}, secret: true}, ""},
+		{"TEST4", fieldTag{name: "NAME", keys: []string{"NAME"}, secret: true}, ""},
+		{"TEST5", fieldTag{name: "TEST5", keys: []string{"TEST5"}}, `Tag envh:",whatever" is invalid : option "whatever" doesn't exist`},
+		{"TEST6", fieldTag{name: "NAME", keys: []string{"NAME", "OLD_NAME"}, secret: true}, ""},
+	}
+
+	for _, test := range tests {
+		field, _ := reflect.TypeOf(TEST{}).FieldByName(test.field)
+		tag, err := parseFieldTag(field, newPopulateOptions())
+
+		if test.err != "" {
+			assert.EqualError(t, err, test.err)
+		} else {
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, test.expected, tag)
+	}
+}
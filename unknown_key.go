@@ -0,0 +1,157 @@
+package envh
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// maxSuggestionDistance is the maximum edit distance between an unknown variable
+// and an expected one to suggest the latter
+const maxSuggestionDistance = 3
+
+// findUnknownKeys walks tree below struct root key and returns, sorted by name, every
+// variable which doesn't match a struct field, sub trees of fields handled by a StructWalker
+// are considered as consumed
+func findUnknownKeys(tree *EnvTree, typ reflect.Type, chain []string, bypassed [][]string, opts *populateOptions) []UnknownKeyError {
+	expected := map[string]bool{}
+	candidates := []string{}
+
+	collectExpectedKeys(tree, typ, chain, opts, expected, &candidates)
+
+	for _, c := range bypassed {
+		expected[strings.Join(c, " -> ")] = true
+	}
+
+	unknownKeys := []UnknownKeyError{}
+	n, exists := tree.findNode(chain)
+
+	if !exists {
+		return unknownKeys
+	}
+
+	walkUnknownKeys(tree, n, chain, expected, candidates, &unknownKeys)
+
+	sort.Slice(unknownKeys, func(i, j int) bool {
+		return unknownKeys[i].Variable < unknownKeys[j].Variable
+	})
+
+	return unknownKeys
+}
+
+func collectExpectedKeys(tree *EnvTree, typ reflect.Type, chain []string, opts *populateOptions, expected map[string]bool, candidates *[]string) {
+	for _, f := range opts.plan(typ).fields {
+		// every alias of a field is expected, so a variable being renamed
+		// is not reported whatever name is used
+		for _, key := range f.tag.keys {
+			collectExpectedFieldKeys(tree, f.field, appendKey(chain, key), opts, expected, candidates)
+		}
+	}
+}
+
+func collectExpectedFieldKeys(tree *EnvTree, field reflect.StructField, keyChain []string, opts *populateOptions, expected map[string]bool, candidates *[]string) {
+	if isNestedStruct(field.Type, opts) {
+		collectExpectedKeys(tree, field.Type, keyChain, opts, expected, candidates)
+
+		return
+	}
+
+	if isStructMap(field.Type, opts) {
+		typ, _ := underlyingStructType(field.Type.Elem())
+
+		for _, key := range tree.FindChildrenKeysUnsecured(keyChain...) {
+			collectExpectedKeys(tree, typ, appendKey(keyChain, key), opts, expected, candidates)
+		}
+
+		return
+	}
+
+	if set, ok := opts.variants[field.Type]; ok {
+		discriminatorKeyChain := set.discriminatorKeyChain(keyChain, opts)
+		expected[strings.Join(discriminatorKeyChain, " -> ")] = false
+		*candidates = append(*candidates, tree.ref(discriminatorKeyChain).name)
+
+		// discriminator is read from node so a deprecated variable isn't reported twice
+		discriminator, _ := getNodeValueByKeyChain(tree.root, &discriminatorKeyChain)()
+
+		if typ, ok := underlyingStructType(set.types[discriminator]); ok {
+			collectExpectedKeys(tree, typ, keyChain, opts, expected, candidates)
+		}
+
+		return
+	}
+
+	expected[strings.Join(keyChain, " -> ")] = false
+	*candidates = append(*candidates, tree.ref(keyChain).name)
+}
+
+func walkUnknownKeys(tree *EnvTree, n *node, chain []string, expected map[string]bool, candidates []string, unknownKeys *[]UnknownKeyError) {
+	for _, child := range n.children {
+		keyChain := append(append([]string{}, chain...), child.key)
+		consumeSubTree, isExpected := expected[strings.Join(keyChain, " -> ")]
+
+		if consumeSubTree {
+			continue
+		}
+
+		if child.hasValue && !isExpected {
+			name := tree.ref(keyChain).name
+			*unknownKeys = append(*unknownKeys, UnknownKeyError{keyChain, name, suggestKey(name, candidates)})
+		}
+
+		walkUnknownKeys(tree, child, keyChain, expected, candidates, unknownKeys)
+	}
+}
+
+// suggestKey returns the closest candidate to a variable name
+// or an empty string if none are close enough
+func suggestKey(name string, candidates []string) string {
+	suggestion := ""
+	best := maxSuggestionDistance + 1
+
+	for _, candidate := range candidates {
+		if d := levenshteinDistance(name, candidate); d < best {
+			best = d
+			suggestion = candidate
+		}
+	}
+
+	return suggestion
+}
+
+func levenshteinDistance(a string, b string) int {
+	s1 := []rune(a)
+	s2 := []rune(b)
+	previous := make([]int, len(s2)+1)
+	current := make([]int, len(s2)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(s1); i++ {
+		current[0] = i
+
+		for j := 1; j <= len(s2); j++ {
+			cost := 1
+
+			if s1[i-1] == s2[j-1] {
+				cost = 0
+			}
+
+			current[j] = minInt(previous[j]+1, minInt(current[j-1]+1, previous[j-1]+cost))
+		}
+
+		previous, current = current, previous
+	}
+
+	return previous[len(s2)]
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,89 @@
+package envh
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type UNKNOWNKEYS struct {
+	DB struct {
+		HOST     string
+		PASSWORD string
+	}
+	MAP   map[string]string
+	DEBUG bool
+}
+
+func (u *UNKNOWNKEYS) Walk(tree *EnvTree, keyChain []string) (bool, error) {
+	return strings.Join(keyChain, "_") == "UNKNOWNKEYS_MAP", nil
+}
+
+func TestPopulateStructWithStrictKeys(t *testing.T) {
+	setEnv("UNKNOWNKEYS_DB_HOST", "localhost")
+	setEnv("UNKNOWNKEYS_DB_PASWORD", "secret")
+	setEnv("UNKNOWNKEYS_DB_HOST_PORT", "3306")
+	setEnv("UNKNOWNKEYS_MAP_KEY1", "value1")
+	setEnv("UNKNOWNKEYS_MAP_KEY2_KEY3", "value2")
+	setEnv("UNKNOWNKEYS_DEBUG", "true")
+	setEnv("UNKNOWNKEYS_WHATEVER", "true")
+	setEnv("UNKNOWNKEYSX_DB_HOST", "localhost")
+
+	actual := UNKNOWNKEYS{}
+
+	tree, err := NewEnvTree("UNKNOWNKEYS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.PopulateStructWithStrictKeys(&actual)
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `3 error(s) occurred while populating struct :
+  - Variable "UNKNOWNKEYS_DB_HOST_PORT" doesn't match any field
+  - Variable "UNKNOWNKEYS_DB_PASWORD" doesn't match any field, did you mean "UNKNOWNKEYS_DB_PASSWORD" ?
+  - Variable "UNKNOWNKEYS_WHATEVER" doesn't match any field`)
+	assert.True(t, errors.Is(err, ErrUnknownKey))
+
+	populateErr := PopulateError{}
+
+	assert.True(t, errors.As(err, &populateErr))
+	assert.Len(t, populateErr.Errors, 0)
+	assert.Equal(t, []string{"UNKNOWNKEYS", "DB", "PASWORD"}, populateErr.UnknownKeys[1].KeyChain)
+	assert.Equal(t, "localhost", actual.DB.HOST, "Must populate struct")
+	assert.True(t, actual.DEBUG, "Must populate struct")
+}
+
+func TestPopulateStructWithStrictKeysAndNoUnknownKeys(t *testing.T) {
+	setEnv("UNKNOWNKEYS_DB_HOST", "localhost")
+	setEnv("UNKNOWNKEYS_MAP_KEY1", "value1")
+
+	actual := UNKNOWNKEYS{}
+
+	tree, err := NewEnvTree("UNKNOWNKEYS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.PopulateStructWithStrictKeys(&actual)
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+}
+
+func TestSuggestKey(t *testing.T) {
+	candidates := []string{"APP_DB_HOST", "APP_DB_PORT", "APP_DB_PASSWORD"}
+
+	assert.Equal(t, "APP_DB_HOST", suggestKey("APP_DB_HOTS", candidates))
+	assert.Equal(t, "APP_DB_PASSWORD", suggestKey("APP_DB_PASWORD", candidates))
+	assert.Equal(t, "", suggestKey("APP_MAILER_ENABLED", candidates))
+}
+
+func TestLevenshteinDistance(t *testing.T) {
+	assert.Equal(t, 0, levenshteinDistance("", ""))
+	assert.Equal(t, 3, levenshteinDistance("", "abc"))
+	assert.Equal(t, 1, levenshteinDistance("PASWORD", "PASSWORD"))
+	assert.Equal(t, 3, levenshteinDistance("kitten", "sitting"))
+}
@@ -0,0 +1,250 @@
+package envh
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/antham/envh/internal/tagspec"
+)
+
+const validationTagName = "validate"
+
+var hostnameRegexp = regexp.MustCompile(`^([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9\-]{0,61}[a-zA-Z0-9])(\.([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9\-]{0,61}[a-zA-Z0-9]))*\.?$`)
+
+// rule is a single validation rule extracted from a validate tag,
+// for instance "min=1" gives a rule named "min" with "1" as parameter
+type rule struct {
+	name  string
+	param string
+}
+
+// ruleChecker checks a rule, rules syntax is described in tagspec.Rules
+type ruleChecker struct {
+	kinds []reflect.Kind
+	check func(val reflect.Value, param string) (message string, err error)
+}
+
+var ruleCheckers = map[string]ruleChecker{
+	"min":      {[]reflect.Kind{reflect.Int, reflect.Float32, reflect.String}, checkMin},
+	"max":      {[]reflect.Kind{reflect.Int, reflect.Float32, reflect.String}, checkMax},
+	"len":      {[]reflect.Kind{reflect.String}, checkLen},
+	"oneof":    {[]reflect.Kind{reflect.Int, reflect.Float32, reflect.String, reflect.Bool}, checkOneOf},
+	"regex":    {[]reflect.Kind{reflect.String}, checkRegex},
+	"url":      {[]reflect.Kind{reflect.String}, checkURL},
+	"hostname": {[]reflect.Kind{reflect.String}, checkHostname},
+	"ip":       {[]reflect.Kind{reflect.String}, checkIP},
+	"port":     {[]reflect.Kind{reflect.Int, reflect.String}, checkPort},
+	"nonempty": {[]reflect.Kind{reflect.String}, checkNonEmpty},
+}
+
+// parseValidationTag extracts rules from a validate tag, rules are separated
+// by commas, a comma belonging to a rule parameter must be escaped with a backslash
+func parseValidationTag(tag string) ([]rule, error) {
+	parsed, err := tagspec.ParseRules(tag)
+
+	if err != nil {
+		return []rule{}, TagError{validationTagName, tag, err.Error()}
+	}
+
+	rules := []rule{}
+
+	for _, r := range parsed {
+		rules = append(rules, rule{r.Name, r.Param})
+	}
+
+	return rules, nil
+}
+
+// validateField checks a populated field against rules defined in its validate tag,
+// rules are only checked when a value is defined for the field, so nonempty
+// rejects an empty variable but not a missing one which is left to strict mode
+func validateField(tree *EnvTree, f fieldPlan, val reflect.Value, keyChain []string) error {
+	if f.rulesErr != nil {
+		return f.rulesErr
+	}
+
+	if len(f.rules) == 0 || !f.tag.isDefined(tree, keyChain) {
+		return nil
+	}
+
+	val, _ = unwrapSecret(val)
+
+	return validateValue(val, tree.ref(keyChain), f.rules)
+}
+
+// validateValue runs every rule against a populated value and
+// returns an error on the first one which is not satisfied
+func validateValue(val reflect.Value, ref varRef, rules []rule) error {
+	for _, r := range rules {
+		if isCrossFieldRule(r.name) {
+			continue
+		}
+
+		checker := ruleCheckers[r.name]
+
+		if !isKindSupportedByRule(val.Kind(), checker) {
+			return TagError{validationTagName, r.name, fmt.Sprintf(`rule "%s" can't be applied to type "%s"`, r.name, val.Kind())}
+		}
+
+		message, err := checker.check(val, r.param)
+
+		if err != nil {
+			return TagError{validationTagName, r.name + "=" + r.param, err.Error()}
+		}
+
+		if message != "" {
+			return ValidationError{val.Interface(), r.name, r.param, message, ref.keyChain, ref.name, false}
+		}
+	}
+
+	return nil
+}
+
+func isKindSupportedByRule(kind reflect.Kind, checker ruleChecker) bool {
+	for _, k := range checker.kinds {
+		if k == kind {
+			return true
+		}
+	}
+
+	return false
+}
+
+func numericValue(val reflect.Value) float64 {
+	switch val.Kind() {
+	case reflect.Int:
+		return float64(val.Int())
+	case reflect.Float32:
+		return val.Float()
+	default:
+		return float64(utf8.RuneCountInString(val.String()))
+	}
+}
+
+func checkBound(val reflect.Value, param string, fails func(a float64, b float64) bool, adjective string) (string, error) {
+	bound, err := strconv.ParseFloat(param, 64)
+
+	if err != nil {
+		return "", fmt.Errorf(`"%s" is not a number`, param)
+	}
+
+	if !fails(numericValue(val), bound) {
+		return "", nil
+	}
+
+	if val.Kind() == reflect.String {
+		return fmt.Sprintf("length must be %s or equal to %s", adjective, param), nil
+	}
+
+	return fmt.Sprintf("must be %s or equal to %s", adjective, param), nil
+}
+
+func checkMin(val reflect.Value, param string) (string, error) {
+	return checkBound(val, param, func(a float64, b float64) bool { return a < b }, "greater than")
+}
+
+func checkMax(val reflect.Value, param string) (string, error) {
+	return checkBound(val, param, func(a float64, b float64) bool { return a > b }, "lower than")
+}
+
+func checkLen(val reflect.Value, param string) (string, error) {
+	l, err := strconv.Atoi(param)
+
+	if err != nil {
+		return "", fmt.Errorf(`"%s" is not an integer`, param)
+	}
+
+	if utf8.RuneCountInString(val.String()) != l {
+		return fmt.Sprintf("length must be equal to %d", l), nil
+	}
+
+	return "", nil
+}
+
+func checkOneOf(val reflect.Value, param string) (string, error) {
+	choices := strings.Fields(param)
+
+	for _, choice := range choices {
+		if fmt.Sprint(val.Interface()) == choice {
+			return "", nil
+		}
+	}
+
+	return fmt.Sprintf(`must be one of "%s"`, strings.Join(choices, `", "`)), nil
+}
+
+func checkRegex(val reflect.Value, param string) (string, error) {
+	r, err := regexp.Compile(param)
+
+	if err != nil {
+		return "", err
+	}
+
+	if !r.MatchString(val.String()) {
+		return fmt.Sprintf(`must match regexp "%s"`, param), nil
+	}
+
+	return "", nil
+}
+
+func checkURL(val reflect.Value, param string) (string, error) {
+	if u, err := url.ParseRequestURI(val.String()); err != nil || u.Scheme == "" || u.Host == "" {
+		return "must be a valid URL", nil
+	}
+
+	return "", nil
+}
+
+func checkHostname(val reflect.Value, param string) (string, error) {
+	if len(val.String()) > 253 || !hostnameRegexp.MatchString(val.String()) {
+		return "must be a valid hostname", nil
+	}
+
+	return "", nil
+}
+
+func checkIP(val reflect.Value, param string) (string, error) {
+	if net.ParseIP(val.String()) == nil {
+		return "must be a valid IP address", nil
+	}
+
+	return "", nil
+}
+
+func checkPort(val reflect.Value, param string) (string, error) {
+	var port int64
+
+	message := "must be a valid port comprised between 1 and 65535"
+
+	if val.Kind() == reflect.String {
+		p, err := strconv.Atoi(val.String())
+
+		if err != nil {
+			return message, nil
+		}
+
+		port = int64(p)
+	} else {
+		port = val.Int()
+	}
+
+	if port < 1 || port > 65535 {
+		return message, nil
+	}
+
+	return "", nil
+}
+
+func checkNonEmpty(val reflect.Value, param string) (string, error) {
+	if strings.TrimSpace(val.String()) == "" {
+		return "must not be empty", nil
+	}
+
+	return "", nil
+}
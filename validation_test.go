@@ -0,0 +1,98 @@
+package envh
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/antham/envh/internal/tagspec"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseValidationTag(t *testing.T) {
+	type g struct {
+		tag   string
+		rules []rule
+		err   string
+	}
+
+	tests := []g{
+		{"", []rule{}, ""},
+		{"nonempty", []rule{{"nonempty", ""}}, ""},
+		{"min=1,max=10", []rule{{"min", "1"}, {"max", "10"}}, ""},
+		{"oneof=debug info warn", []rule{{"oneof", "debug info warn"}}, ""},
+		{`regex=^[a-z]{1\,3}$,nonempty`, []rule{{"regex", "^[a-z]{1,3}$"}, {"nonempty", ""}}, ""},
+		{`regex=^\d+$`, []rule{{"regex", `^\d+$`}}, ""},
+		{"whatever", []rule{}, `Tag validate:"whatever" is invalid : rule "whatever" doesn't exist`},
+		{"min", []rule{}, `Tag validate:"min" is invalid : rule "min" requires a parameter`},
+		{"ip=1", []rule{}, `Tag validate:"ip=1" is invalid : rule "ip" doesn't accept any parameter`},
+	}
+
+	for _, test := range tests {
+		rules, err := parseValidationTag(test.tag)
+
+		if test.err != "" {
+			assert.EqualError(t, err, test.err)
+		} else {
+			assert.NoError(t, err)
+		}
+
+		assert.Equal(t, test.rules, rules)
+	}
+}
+
+func TestRuleCheckersMatchTagSpec(t *testing.T) {
+	for name, spec := range tagspec.Rules {
+		_, isRule := ruleCheckers[name]
+		_, isCrossFieldRule := crossFieldRuleCheckers[name]
+
+		assert.True(t, isRule != isCrossFieldRule, name)
+		assert.Equal(t, spec.CrossField, isCrossFieldRule, name)
+	}
+
+	assert.Len(t, tagspec.Rules, len(ruleCheckers)+len(crossFieldRuleCheckers))
+}
+
+func TestValidateValue(t *testing.T) {
+	type g struct {
+		value interface{}
+		rule  rule
+		err   string
+	}
+
+	tests := []g{
+		{10, rule{"min", "10"}, ""},
+		{9, rule{"min", "10"}, `Value "9" of variable "TEST" is invalid : must be greater than or equal to 10`},
+		{float32(1.5), rule{"max", "1.4"}, `Value "1.5" of variable "TEST" is invalid : must be lower than or equal to 1.4`},
+		{"abc", rule{"max", "2"}, `Value "abc" of variable "TEST" is invalid : length must be lower than or equal to 2`},
+		{"abc", rule{"min", "a"}, `Tag validate:"min=a" is invalid : "a" is not a number`},
+		{"abc", rule{"len", "3"}, ""},
+		{"abcd", rule{"len", "3"}, `Value "abcd" of variable "TEST" is invalid : length must be equal to 3`},
+		{"info", rule{"oneof", "debug info"}, ""},
+		{"warn", rule{"oneof", "debug info"}, `Value "warn" of variable "TEST" is invalid : must be one of "debug", "info"`},
+		{3, rule{"oneof", "1 2 3"}, ""},
+		{"abc", rule{"regex", "^[a-z]+$"}, ""},
+		{"ab1", rule{"regex", "^[a-z]+$"}, `Value "ab1" of variable "TEST" is invalid : must match regexp "^[a-z]+$"`},
+		{"https://example.com/path", rule{"url", ""}, ""},
+		{"example.com", rule{"url", ""}, `Value "example.com" of variable "TEST" is invalid : must be a valid URL`},
+		{"db-1.example.com", rule{"hostname", ""}, ""},
+		{"db_1.example.com", rule{"hostname", ""}, `Value "db_1.example.com" of variable "TEST" is invalid : must be a valid hostname`},
+		{"::1", rule{"ip", ""}, ""},
+		{"localhost", rule{"ip", ""}, `Value "localhost" of variable "TEST" is invalid : must be a valid IP address`},
+		{8080, rule{"port", ""}, ""},
+		{"8080", rule{"port", ""}, ""},
+		{70000, rule{"port", ""}, `Value "70000" of variable "TEST" is invalid : must be a valid port comprised between 1 and 65535`},
+		{"http", rule{"port", ""}, `Value "http" of variable "TEST" is invalid : must be a valid port comprised between 1 and 65535`},
+		{" ", rule{"nonempty", ""}, `Value " " of variable "TEST" is invalid : must not be empty`},
+		{true, rule{"ip", ""}, `Tag validate:"ip" is invalid : rule "ip" can't be applied to type "bool"`},
+	}
+
+	for _, test := range tests {
+		err := validateValue(reflect.ValueOf(test.value), varRef{[]string{"TEST"}, "TEST"}, []rule{test.rule})
+
+		if test.err != "" {
+			assert.EqualError(t, err, test.err)
+		} else {
+			assert.NoError(t, err)
+		}
+	}
+}
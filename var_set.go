@@ -0,0 +1,247 @@
+package envh
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"text/tabwriter"
+)
+
+// registeredVar is a variable registered in a VarSet
+type registeredVar struct {
+	name         string
+	usage        string
+	typ          string
+	defaultValue interface{}
+	value        reflect.Value
+}
+
+// VarSet holds variables registered the same way flags are defined
+// with flag package, values are set when Parse is called
+type VarSet struct {
+	name   string
+	vars   map[string]registeredVar
+	output io.Writer
+	parsed bool
+}
+
+// DefaultVarSet is the set used by top-level functions Int, String, Parse...
+var DefaultVarSet = NewVarSet("")
+
+// NewVarSet creates an empty set of variables,
+// name is displayed on top of usage table if not empty
+func NewVarSet(name string) *VarSet {
+	return &VarSet{name: name, vars: map[string]registeredVar{}}
+}
+
+// Var registers a variable of type T, p is set with value as default
+// and is overridden when Parse is called if variable is defined.
+// Every type a struct field can have is supported, a type without decoder
+// is reported by Parse in a TypeUnsupported error naming the variable as
+// a decoder can still be registered once Var is called.
+// It panics if a variable with the same name is already registered
+func Var[T any](s *VarSet, p *T, name string, value T, usage string) {
+	if _, ok := s.vars[name]; ok {
+		panic(fmt.Sprintf(`variable "%s" is already registered`, name))
+	}
+
+	*p = value
+	s.vars[name] = registeredVar{name, usage, reflect.TypeOf(p).Elem().String(), value, reflect.ValueOf(p).Elem()}
+}
+
+// IntVar registers an int variable, p is set with value as default
+func (s *VarSet) IntVar(p *int, name string, value int, usage string) {
+	Var(s, p, name, value, usage)
+}
+
+// Int registers an int variable and returns a pointer to its value
+func (s *VarSet) Int(name string, value int, usage string) *int {
+	p := new(int)
+	s.IntVar(p, name, value, usage)
+
+	return p
+}
+
+// FloatVar registers a float variable, p is set with value as default
+func (s *VarSet) FloatVar(p *float32, name string, value float32, usage string) {
+	Var(s, p, name, value, usage)
+}
+
+// Float registers a float variable and returns a pointer to its value
+func (s *VarSet) Float(name string, value float32, usage string) *float32 {
+	p := new(float32)
+	s.FloatVar(p, name, value, usage)
+
+	return p
+}
+
+// StringVar registers a string variable, p is set with value as default
+func (s *VarSet) StringVar(p *string, name string, value string, usage string) {
+	Var(s, p, name, value, usage)
+}
+
+// String registers a string variable and returns a pointer to its value
+func (s *VarSet) String(name string, value string, usage string) *string {
+	p := new(string)
+	s.StringVar(p, name, value, usage)
+
+	return p
+}
+
+// BoolVar registers a boolean variable, p is set with value as default
+func (s *VarSet) BoolVar(p *bool, name string, value bool, usage string) {
+	Var(s, p, name, value, usage)
+}
+
+// Bool registers a boolean variable and returns a pointer to its value
+func (s *VarSet) Bool(name string, value bool, usage string) *bool {
+	p := new(bool)
+	s.BoolVar(p, name, value, usage)
+
+	return p
+}
+
+// SetOutput defines where usage table is written, standard error is used by default
+func (s *VarSet) SetOutput(output io.Writer) {
+	s.output = output
+}
+
+// Parse sets every registered variable defined in env, a missing variable keeps
+// its default value and a type which can't be decoded is reported whether
+// the variable is defined or not. It doesn't stop at the first failing variable,
+// every error encountered is gathered in a ParseError
+func (s *VarSet) Parse(env Env) error {
+	errs := []error{}
+	opts := newPopulateOptions()
+
+	for _, v := range s.sortedVars() {
+		if err := checkDecodable(v.value.Type(), varRef{name: v.name}, opts); err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		value, ok := env.lookup(v.name)()
+
+		if !ok {
+			continue
+		}
+
+		lookup := func() (string, bool) {
+			return value, true
+		}
+
+		if err := decodeValue(true, v.value, lookup, varRef{name: v.name}, opts); err != nil {
+			if isSecretType(v.value.Type()) {
+				err = markSecret(err)
+			}
+
+			v.value.Set(reflect.ValueOf(v.defaultValue))
+			errs = append(errs, err)
+		}
+	}
+
+	s.parsed = true
+
+	if len(errs) > 0 {
+		return ParseError{errs}
+	}
+
+	return nil
+}
+
+// Parsed returns true if Parse was called
+func (s *VarSet) Parsed() bool {
+	return s.parsed
+}
+
+// PrintDefaults writes a table describing every registered variable with its type,
+// its default value and its description, default values of variables
+// whose name is sensitive are redacted
+func (s *VarSet) PrintDefaults() {
+	output := s.output
+
+	if output == nil {
+		output = os.Stderr
+	}
+
+	if s.name != "" {
+		fmt.Fprintf(output, "Variables of %s :\n", s.name)
+	}
+
+	w := tabwriter.NewWriter(output, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "VARIABLE\tTYPE\tDEFAULT\tDESCRIPTION")
+
+	for _, v := range s.sortedVars() {
+		fmt.Fprintf(w, "%s\t%s\t%v\t%s\n", v.name, v.typ, redact(v.defaultValue, v.name, isSecretType(v.value.Type())), v.usage)
+	}
+
+	w.Flush()
+}
+
+func (s *VarSet) sortedVars() []registeredVar {
+	vars := []registeredVar{}
+
+	for _, v := range s.vars {
+		vars = append(vars, v)
+	}
+
+	sort.Slice(vars, func(i, j int) bool {
+		return vars[i].name < vars[j].name
+	})
+
+	return vars
+}
+
+// IntVar registers an int variable in DefaultVarSet
+func IntVar(p *int, name string, value int, usage string) {
+	DefaultVarSet.IntVar(p, name, value, usage)
+}
+
+// Int registers an int variable in DefaultVarSet
+func Int(name string, value int, usage string) *int {
+	return DefaultVarSet.Int(name, value, usage)
+}
+
+// FloatVar registers a float variable in DefaultVarSet
+func FloatVar(p *float32, name string, value float32, usage string) {
+	DefaultVarSet.FloatVar(p, name, value, usage)
+}
+
+// Float registers a float variable in DefaultVarSet
+func Float(name string, value float32, usage string) *float32 {
+	return DefaultVarSet.Float(name, value, usage)
+}
+
+// StringVar registers a string variable in DefaultVarSet
+func StringVar(p *string, name string, value string, usage string) {
+	DefaultVarSet.StringVar(p, name, value, usage)
+}
+
+// String registers a string variable in DefaultVarSet
+func String(name string, value string, usage string) *string {
+	return DefaultVarSet.String(name, value, usage)
+}
+
+// BoolVar registers a boolean variable in DefaultVarSet
+func BoolVar(p *bool, name string, value bool, usage string) {
+	DefaultVarSet.BoolVar(p, name, value, usage)
+}
+
+// Bool registers a boolean variable in DefaultVarSet
+func Bool(name string, value bool, usage string) *bool {
+	return DefaultVarSet.Bool(name, value, usage)
+}
+
+// Parse sets variables registered in DefaultVarSet from current environment
+func Parse() error {
+	return DefaultVarSet.Parse(NewEnv())
+}
+
+// PrintDefaults writes usage table of variables registered in DefaultVarSet
+func PrintDefaults() {
+	DefaultVarSet.PrintDefaults()
+}
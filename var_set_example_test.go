@@ -0,0 +1,35 @@
+package envh
+
+import (
+	"fmt"
+	"os"
+)
+
+func ExampleVarSet() {
+	os.Clearenv()
+	setEnv("PORT", "3306")
+
+	var host string
+
+	vars := NewVarSet("server")
+	vars.SetOutput(os.Stdout)
+
+	port := vars.Int("PORT", 8080, "listen port")
+	vars.StringVar(&host, "HOST", "127.0.0.1", "listen host")
+
+	if err := vars.Parse(NewEnv()); err != nil {
+		vars.PrintDefaults()
+
+		return
+	}
+
+	fmt.Printf("%s:%d\n", host, *port)
+
+	vars.PrintDefaults()
+	// Output:
+	// 127.0.0.1:3306
+	// Variables of server :
+	// VARIABLE  TYPE    DEFAULT    DESCRIPTION
+	// HOST      string  127.0.0.1  listen host
+	// PORT      int     8080       listen port
+}
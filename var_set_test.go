@@ -0,0 +1,125 @@
+package envh
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVarSetParse(t *testing.T) {
+	setEnv("TEST99_PORT", "3306")
+	setEnv("TEST99_HOST", "localhost")
+	setEnv("TEST99_RATIO", "0.5")
+	setEnv("TEST99_DEBUG", "true")
+	setEnv("TEST99_TOKEN", "hunter2")
+
+	env := NewEnv()
+
+	restoreEnvs()
+
+	s := NewVarSet("test")
+	host := ""
+	token := Secret[string]{}
+
+	port := s.Int("TEST99_PORT", 8080, "listen port")
+	s.StringVar(&host, "TEST99_HOST", "127.0.0.1", "listen host")
+	ratio := s.Float("TEST99_RATIO", 1, "ratio")
+	debug := s.Bool("TEST99_DEBUG", false, "debug mode")
+	timeout := s.Int("TEST99_TIMEOUT", 30, "timeout")
+	Var(s, &token, "TEST99_TOKEN", NewSecret("default"), "api token")
+
+	assert.Equal(t, 8080, *port, "Default must be set when variable is registered")
+	assert.Equal(t, "127.0.0.1", host, "Default must be set when variable is registered")
+	assert.False(t, s.Parsed())
+
+	err := s.Parse(env)
+
+	assert.NoError(t, err)
+	assert.True(t, s.Parsed())
+	assert.Equal(t, 3306, *port)
+	assert.Equal(t, "localhost", host)
+	assert.Equal(t, float32(0.5), *ratio)
+	assert.True(t, *debug)
+	assert.Equal(t, 30, *timeout, "Missing variable must keep default value")
+	assert.Equal(t, "hunter2", token.Reveal())
+}
+
+func TestVarSetParseWithErrors(t *testing.T) {
+	setEnv("TEST99_PORT", "whatever")
+	setEnv("TEST99_DEBUG", "whatever")
+	setEnv("TEST99_SESSION", "hunter2")
+	setEnv("TEST99_TIMEOUT", "1m")
+
+	env := NewEnv()
+
+	restoreEnvs()
+
+	s := NewVarSet("test")
+	session := Secret[int]{}
+	timeout := time.Duration(0)
+
+	port := s.Int("TEST99_PORT", 8080, "listen port")
+	debug := s.Bool("TEST99_DEBUG", true, "debug mode")
+	Var(s, &session, "TEST99_SESSION", NewSecret(1), "session id")
+	Var(s, &timeout, "TEST99_TIMEOUT", time.Second, "timeout")
+
+	err := s.Parse(env)
+
+	assert.EqualError(t, err, `4 error(s) occurred while parsing variables :
+  - Value "whatever" of variable "TEST99_DEBUG" can't be converted to type "bool"
+  - Value "whatever" of variable "TEST99_PORT" can't be converted to type "int"
+  - Value "******" of variable "TEST99_SESSION" can't be converted to type "int"
+  - Type "int64" of variable "TEST99_TIMEOUT" is not supported : you must provide "int32, float32, string, boolean or struct"`)
+	assert.True(t, errors.Is(err, ErrWrongType))
+	assert.Equal(t, 8080, *port, "Failing variable must keep default value")
+	assert.True(t, *debug, "Failing variable must keep default value")
+	assert.Equal(t, time.Second, timeout)
+}
+
+func TestVarSetParseUnsupportedTypeOfMissingVariable(t *testing.T) {
+	s := NewVarSet("test")
+	hosts := []string{"localhost"}
+
+	Var(s, &hosts, "TEST99_HOSTS", hosts, "hosts")
+
+	err := s.Parse(NewEnv())
+
+	assert.EqualError(t, err, `1 error(s) occurred while parsing variables :
+  - Type "slice" of variable "TEST99_HOSTS" is not supported : you must provide "int32, float32, string, boolean or struct"`)
+	assert.Equal(t, []string{"localhost"}, hosts)
+}
+
+func TestVarSetRegisterTwice(t *testing.T) {
+	s := NewVarSet("test")
+	s.Int("TEST99_PORT", 8080, "listen port")
+
+	assert.PanicsWithValue(t, `variable "TEST99_PORT" is already registered`, func() {
+		s.String("TEST99_PORT", "8080", "listen port")
+	})
+}
+
+func TestVarSetPrintDefaults(t *testing.T) {
+	buf := bytes.Buffer{}
+
+	s := NewVarSet("test")
+	s.SetOutput(&buf)
+	s.Int("PORT", 8080, "listen port")
+	s.String("HOST", "127.0.0.1", "listen host")
+	s.String("API_TOKEN", "hunter2", "api token")
+	s.Bool("DEBUG", false, "debug mode")
+	s.String("KEYBOARD_LAYOUT", "azerty", "keyboard layout")
+
+	s.PrintDefaults()
+
+	assert.Equal(t, `Variables of test :
+VARIABLE         TYPE    DEFAULT    DESCRIPTION
+API_TOKEN        string  ******     api token
+DEBUG            bool    false      debug mode
+HOST             string  127.0.0.1  listen host
+KEYBOARD_LAYOUT  string  azerty     keyboard layout
+PORT             int     8080       listen port
+`, buf.String())
+}
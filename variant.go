@@ -0,0 +1,98 @@
+package envh
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// variantSet holds concrete types an interface field can hold,
+// indexed by value of discriminator key
+type variantSet struct {
+	discriminator string
+	types         map[string]reflect.Type
+}
+
+// WithVariants registers concrete types an interface field of type I can hold,
+// concrete type is selected by value of discriminator key defined below field key,
+// for instance with "TYPE" as discriminator, STORAGE_TYPE=s3 populates a Storage field
+// with STORAGE_* variables using variant registered as "s3".
+// A variant must be a struct or a pointer to a struct implementing I,
+// a pointer is populated with a new instance.
+// When populating a flat Env, field key is the discriminator variable itself.
+func WithVariants[I any](discriminator string, variants map[string]I) PopulateOption {
+	return func(opts *populateOptions) {
+		set := variantSet{discriminator, map[string]reflect.Type{}}
+
+		for name, variant := range variants {
+			set.types[name] = reflect.TypeOf(variant)
+		}
+
+		opts.variants[reflect.TypeOf((*I)(nil)).Elem()] = set
+	}
+}
+
+// names returns sorted names of registered variants
+func (v variantSet) names() []string {
+	names := []string{}
+
+	for name := range v.types {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// discriminatorKeyChain returns key chain of variable selecting variant of a field
+func (v variantSet) discriminatorKeyChain(keyChain []string, opts *populateOptions) []string {
+	if opts.flat {
+		return keyChain
+	}
+
+	return append(append([]string{}, keyChain...), v.discriminator)
+}
+
+// populateVariant selects variant matching discriminator value and populates it,
+// errors triggered by variant fields are gathered with other field errors
+func populateVariant(origStruct interface{}, tree *EnvTree, val reflect.Value, keyChain []string, path []string, tag fieldTag, set variantSet, opts *populateOptions, errs *[]FieldError, bypassed *[][]string) error {
+	discriminatorKeyChain := set.discriminatorKeyChain(keyChain, opts)
+	ref := tree.ref(discriminatorKeyChain)
+	name, err := getString(tag.lookup(tree, discriminatorKeyChain, opts.reports), ref)
+
+	if err != nil {
+		if opts.forceDefinition {
+			return err
+		}
+
+		return nil
+	}
+
+	typ, ok := set.types[name]
+
+	if !ok {
+		names := set.names()
+
+		return ValidationError{name, "oneof", strings.Join(names, " "), fmt.Sprintf(`must be one of "%s"`, strings.Join(names, `", "`)), ref.keyChain, ref.name, false}
+	}
+
+	structType, ok := underlyingStructType(typ)
+
+	if !ok {
+		return TypeUnsupported{fmt.Sprint(typ), "struct or pointer to struct implementing " + val.Type().String(), keyChain, ""}
+	}
+
+	ptr := reflect.New(structType)
+
+	populateValue(origStruct, tree, ptr.Elem(), opts.structKeyChain(keyChain), path, opts, errs, bypassed)
+
+	if typ.Kind() == reflect.Ptr {
+		val.Set(ptr)
+	} else {
+		val.Set(ptr.Elem())
+	}
+
+	return nil
+}
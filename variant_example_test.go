@@ -0,0 +1,59 @@
+package envh
+
+import (
+	"fmt"
+	"os"
+)
+
+type Storage interface {
+	URL() string
+}
+
+type S3Config struct {
+	BUCKET string
+	REGION string
+}
+
+func (s S3Config) URL() string {
+	return fmt.Sprintf("s3://%s (%s)", s.BUCKET, s.REGION)
+}
+
+type FSConfig struct {
+	PATH string
+}
+
+func (f FSConfig) URL() string {
+	return "file://" + f.PATH
+}
+
+type CONFIG6 struct {
+	STORAGE Storage
+}
+
+func ExampleWithVariants() {
+	os.Clearenv()
+	setEnv("CONFIG6_STORAGE_TYPE", "s3")
+	setEnv("CONFIG6_STORAGE_BUCKET", "assets")
+	setEnv("CONFIG6_STORAGE_REGION", "eu-west-1")
+
+	env, err := NewEnvTree("^CONFIG6", "_")
+
+	if err != nil {
+		return
+	}
+
+	conf := CONFIG6{}
+
+	err = env.Populate(&conf, WithVariants("TYPE", map[string]Storage{
+		"s3": S3Config{},
+		"fs": FSConfig{},
+	}))
+
+	if err != nil {
+		return
+	}
+
+	fmt.Println(conf.STORAGE.URL())
+	// Output:
+	// s3://assets (eu-west-1)
+}
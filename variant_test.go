@@ -0,0 +1,182 @@
+package envh
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type storage interface {
+	location() string
+}
+
+type s3Storage struct {
+	BUCKET string `validate:"nonempty"`
+	REGION string `default:"eu-west-1"`
+}
+
+func (s s3Storage) location() string {
+	return "s3://" + s.BUCKET + "@" + s.REGION
+}
+
+type fsStorage struct {
+	PATH      string
+	finalized bool
+}
+
+func (f *fsStorage) location() string {
+	return "file://" + f.PATH
+}
+
+func (f *fsStorage) AfterPopulate(tree *EnvTree) error {
+	f.finalized = true
+
+	return nil
+}
+
+type VARIANTS struct {
+	STORAGE storage
+	NAME    string
+}
+
+func variantsOption() PopulateOption {
+	return WithVariants("TYPE", map[string]storage{"s3": s3Storage{}, "fs": &fsStorage{}})
+}
+
+func TestPopulateWithVariants(t *testing.T) {
+	type test struct {
+		envs  map[string]string
+		setup func(actual VARIANTS, err error)
+	}
+
+	tests := []test{
+		{
+			map[string]string{"VARIANTS_STORAGE_TYPE": "s3", "VARIANTS_STORAGE_BUCKET": "assets"},
+			func(actual VARIANTS, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, s3Storage{"assets", "eu-west-1"}, actual.STORAGE)
+			},
+		},
+		{
+			map[string]string{"VARIANTS_STORAGE_TYPE": "fs", "VARIANTS_STORAGE_PATH": "/tmp"},
+			func(actual VARIANTS, err error) {
+				assert.NoError(t, err)
+				assert.Equal(t, "file:///tmp", actual.STORAGE.location())
+				assert.True(t, actual.STORAGE.(*fsStorage).finalized, "Hooks of variant must be called")
+			},
+		},
+		{
+			map[string]string{"VARIANTS_NAME": "test"},
+			func(actual VARIANTS, err error) {
+				assert.NoError(t, err)
+				assert.Nil(t, actual.STORAGE)
+			},
+		},
+		{
+			map[string]string{"VARIANTS_STORAGE_TYPE": "gcs"},
+			func(actual VARIANTS, err error) {
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "STORAGE" : Value "gcs" of variable "VARIANTS_STORAGE_TYPE" is invalid : must be one of "fs", "s3"`)
+				assert.True(t, errors.Is(err, ErrValidation))
+				assert.Nil(t, actual.STORAGE)
+			},
+		},
+		{
+			map[string]string{"VARIANTS_STORAGE_TYPE": "s3", "VARIANTS_STORAGE_BUCKET": ""},
+			func(actual VARIANTS, err error) {
+				assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "STORAGE.BUCKET" : Value "" of variable "VARIANTS_STORAGE_BUCKET" is invalid : must not be empty`)
+			},
+		},
+	}
+
+	for _, test := range tests {
+		for k, v := range test.envs {
+			setEnv(k, v)
+		}
+
+		actual := VARIANTS{}
+
+		tree, err := NewEnvTree("^VARIANTS", "_")
+
+		assert.NoError(t, err)
+
+		err = tree.Populate(&actual, variantsOption())
+
+		restoreEnvs()
+
+		test.setup(actual, err)
+	}
+}
+
+func TestPopulateWithVariantsAndStrictMode(t *testing.T) {
+	setEnv("VARIANTS_NAME", "test")
+
+	actual := VARIANTS{}
+
+	tree, err := NewEnvTree("^VARIANTS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, variantsOption(), WithStrictMode())
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "STORAGE" : Variable "VARIANTS_STORAGE_TYPE" not found`)
+}
+
+func TestPopulateWithVariantsAndUnknownKeys(t *testing.T) {
+	setEnv("VARIANTS_STORAGE_TYPE", "s3")
+	setEnv("VARIANTS_STORAGE_BUCKET", "assets")
+	setEnv("VARIANTS_STORAGE_PATH", "/tmp")
+
+	actual := VARIANTS{}
+
+	tree, err := NewEnvTree("^VARIANTS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, variantsOption(), WithUnknownKeyPolicy(ReportUnknownKeys))
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Variable "VARIANTS_STORAGE_PATH" doesn't match any field`)
+}
+
+func TestPopulateWithWrongVariant(t *testing.T) {
+	setEnv("VARIANTS_STORAGE_TYPE", "s3")
+
+	actual := VARIANTS{}
+
+	tree, err := NewEnvTree("^VARIANTS", "_")
+
+	assert.NoError(t, err)
+
+	err = tree.Populate(&actual, WithVariants("TYPE", map[string]storage{"s3": nil}))
+
+	restoreEnvs()
+
+	assert.EqualError(t, err, `1 error(s) occurred while populating struct :
+  - Field "STORAGE" : Type "<nil>" is not supported : you must provide "struct or pointer to struct implementing envh.storage"`)
+}
+
+func TestEnvPopulateWithVariants(t *testing.T) {
+	type CONFIG struct {
+		Storage storage `env:"TEST99_STORAGE"`
+	}
+
+	setEnv("TEST99_STORAGE", "s3")
+	setEnv("BUCKET", "assets")
+
+	actual := CONFIG{}
+
+	err := NewEnv().Populate(&actual, variantsOption())
+
+	restoreEnvs()
+
+	assert.NoError(t, err)
+	assert.Equal(t, s3Storage{"assets", "eu-west-1"}, actual.Storage)
+}